@@ -17,11 +17,27 @@ func init() {
 	flags.Register("annotate", "merge features from a feature list file into a sequence", annotateFunc)
 }
 
+// retainsFeatures tests if the features of the sequence are written in the
+// given output format. Records without a feature table, such as FASTA and
+// FASTQ records, are written without features unless the format is one for
+// features only or JSON.
+func retainsFeatures(seq gts.Sequence, filetype seqio.FileType) bool {
+	switch filetype {
+	case seqio.GFF3File, seqio.BEDFile, seqio.JSONFile, seqio.JSONLinesFile:
+		return true
+	case seqio.FastaFile, seqio.FastqFile:
+		return false
+	default:
+		_, ok := seq.Info().(seqio.GenBankFields)
+		return ok
+	}
+}
+
 func annotateFunc(ctx *flags.Context) error {
 	h := newHash()
 	pos, opt := flags.Flags()
 
-	featinPath := pos.String("feature_table", "feature table file containing features to merge")
+	featinPath := pos.String("feature_table", "feature table file containing features to merge (INSDC feature table, GFF3, or BED)")
 
 	seqinPath := new(string)
 	*seqinPath = "-"
@@ -32,6 +48,7 @@ func annotateFunc(ctx *flags.Context) error {
 	nocache := opt.Switch(0, "no-cache", "do not use or create cache")
 	seqoutPath := opt.String('o', "output", "-", "output sequence file (specifying `-` will force standard output)")
 	format := opt.String('F', "format", "", "output file format (defaults to same as input)")
+	jobs := opt.Int('j', "jobs", 1, "number of sequences to process in parallel (0 to use all available CPUs)")
 
 	if err := ctx.Parse(pos, opt); err != nil {
 		return err
@@ -45,12 +62,29 @@ func annotateFunc(ctx *flags.Context) error {
 	h.Reset()
 	r := attach(h, featinFile)
 	state := pars.NewState(r)
-	result, err := seqio.INSDCTableParser("").Parse(state)
+	parser := pars.Any(seqio.GFF3Parser, seqio.INSDCTableParser(""), seqio.BEDParser)
+	result, err := parser.Parse(state)
 	if err != nil {
 		return ctx.Raise(err)
 	}
 
-	featin := result.Value.([]gts.Feature)
+	lookup := func(seq gts.Sequence) []gts.Feature { return nil }
+
+	switch v := result.Value.(type) {
+	case []gts.Feature:
+		lookup = func(seq gts.Sequence) []gts.Feature { return v }
+	case seqio.FeatureTable:
+		lookup = func(seq gts.Sequence) []gts.Feature {
+			if ff, ok := v.Lookup(seqio.SequenceID(seq)); ok {
+				return ff
+			}
+			if len(v) == 1 {
+				return v[0].Features
+			}
+			return nil
+		}
+	}
+
 	featsum := h.Sum(nil)
 
 	d, err := newIODelegate(*seqinPath, *seqoutPath)
@@ -82,25 +116,22 @@ func annotateFunc(ctx *flags.Context) error {
 	buffer := bufio.NewWriter(d)
 	writer := seqio.NewWriter(buffer, filetype)
 
-	for scanner.Scan() {
-		seq := scanner.Value()
-		ff := seq.Features()
-		for _, f := range featin {
-			ff = ff.Insert(f)
-		}
-		seq = gts.WithFeatures(seq, ff)
-		if _, err := writer.WriteSeq(seq); err != nil {
-			return ctx.Raise(err)
+	err = runPipeline(scanner, buffer, writer, *jobs, func(seq gts.Sequence) ([]gts.Sequence, error) {
+		gg := lookup(seq)
+		if len(gg) > 0 && !retainsFeatures(seq, filetype) {
+			formats := "json, jsonl, gff3, or bed"
+			if _, ok := seq.Info().(seqio.GenBankFields); ok {
+				formats = "genbank, embl, " + formats
+			}
+			return nil, fmt.Errorf("features annotated to %q would be discarded in the output format: use -F with one of %s", seqio.SequenceID(seq), formats)
 		}
 
-		if err := buffer.Flush(); err != nil {
-			return ctx.Raise(err)
+		ff := seq.Features()
+		for _, f := range gg {
+			ff = ff.Insert(f)
 		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
-	}
+		return []gts.Sequence{gts.WithFeatures(seq, ff)}, nil
+	})
 
-	return nil
+	return ctx.Raise(err)
 }
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-gts/gts/internal/testutils"
+	"github.com/go-gts/gts/seqio"
+)
+
+var retainsFeaturesTests = []struct {
+	filename string
+	filetype seqio.FileType
+	out      bool
+}{
+	{"NC_001422.fasta", seqio.DefaultFile, false},
+	{"NC_001422.fasta", seqio.FastaFile, false},
+	{"NC_001422.fasta", seqio.GenBankFile, false},
+	{"NC_001422.fasta", seqio.EMBLFile, false},
+	{"NC_001422.fasta", seqio.JSONFile, true},
+	{"NC_001422.fasta", seqio.JSONLinesFile, true},
+	{"NC_001422.fasta", seqio.GFF3File, true},
+	{"NC_001422.fasta", seqio.BEDFile, true},
+	{"NC_001422.gb", seqio.DefaultFile, true},
+	{"NC_001422.gb", seqio.FastaFile, false},
+	{"NC_001422.gb", seqio.EMBLFile, true},
+	{"NC_001422.embl", seqio.DefaultFile, true},
+	{"NC_001422.embl", seqio.GenBankFile, true},
+	{"sample.fastq", seqio.DefaultFile, false},
+	{"sample.fastq", seqio.JSONFile, true},
+}
+
+func TestRetainsFeatures(t *testing.T) {
+	for _, tt := range retainsFeaturesTests {
+		in := testutils.ReadTestfilePkg(t, tt.filename, "../../seqio")
+		scanner := seqio.NewAutoScanner(strings.NewReader(in))
+		if !scanner.Scan() {
+			t.Fatalf("%s: scanner.Err() = %v", tt.filename, scanner.Err())
+		}
+		if out := retainsFeatures(scanner.Value(), tt.filetype); out != tt.out {
+			t.Errorf("retainsFeatures(%s, %d) = %t, want %t", tt.filename, tt.filetype, out, tt.out)
+		}
+	}
+}
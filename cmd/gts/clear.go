@@ -79,5 +79,5 @@ func clearFunc(ctx *flags.Context) error {
 		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
 	}
 
-	return nil
+	return ctx.Raise(closeWriter(writer, buffer))
 }
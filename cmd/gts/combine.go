@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-gts/flags"
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/cmd"
+	"github.com/go-gts/gts/seqio"
+)
+
+func init() {
+	flags.Register("combine", "combine feature locations using set operations", combineFunc)
+}
+
+// combineOperation computes the location of a new feature from the location
+// of a feature and the locations of the features it is combined with.
+type combineOperation func(loc gts.Location, others []gts.Location) gts.Location
+
+func unionLocations(locs []gts.Location) gts.Location {
+	var ret gts.Location
+	for _, loc := range locs {
+		ret = gts.Union(ret, loc)
+	}
+	return ret
+}
+
+func combineIntersect(loc gts.Location, others []gts.Location) gts.Location {
+	return gts.Intersect(loc, unionLocations(others))
+}
+
+func combineSubtract(loc gts.Location, others []gts.Location) gts.Location {
+	return gts.Subtract(loc, unionLocations(others))
+}
+
+func combineUnion(loc gts.Location, others []gts.Location) gts.Location {
+	ret := loc
+	for _, other := range others {
+		if gts.Intersect(loc, other) != nil {
+			ret = gts.Union(ret, other)
+		}
+	}
+	return ret
+}
+
+func asCombineOperation(name string) (combineOperation, error) {
+	switch name {
+	case "intersect":
+		return combineIntersect, nil
+	case "subtract":
+		return combineSubtract, nil
+	case "union":
+		return combineUnion, nil
+	default:
+		return nil, fmt.Errorf("unknown operation: %q", name)
+	}
+}
+
+func combineFunc(ctx *flags.Context) error {
+	h := newHash()
+	pos, opt := flags.Flags()
+
+	opname := pos.String("operation", "set operation to apply (`intersect`, `subtract`, or `union`)")
+	selector := pos.String("selector", "selector for the features to compute new features from (syntax: [feature_key][/[qualifier1][=regexp1]][/[qualifier2][=regexp2]]...)")
+	other := pos.String("other", "selector for the features to combine with (syntax: [feature_key][/[qualifier1][=regexp1]][/[qualifier2][=regexp2]]...)")
+
+	seqinPath := new(string)
+	*seqinPath = "-"
+	if cmd.IsTerminal(os.Stdin.Fd()) {
+		seqinPath = pos.String("seqin", "input sequence file (may be omitted if standard input is provided)")
+	}
+
+	nocache := opt.Switch(0, "no-cache", "do not use or create cache")
+	seqoutPath := opt.String('o', "output", "-", "output sequence file (specifying `-` will force standard output)")
+	format := opt.String('F', "format", "", "output file format (defaults to same as input)")
+	key := opt.String('k', "key", "", "feature key of the new features (defaults to the key of the original feature)")
+	propstrs := opt.StringSlice('q', "qualifier", nil, "qualifier key-value pairs (syntax: key=value))")
+	replace := opt.Switch('r', "replace", "replace the original features with the new features")
+
+	if err := ctx.Parse(pos, opt); err != nil {
+		return err
+	}
+
+	op, err := asCombineOperation(*opname)
+	if err != nil {
+		return ctx.Raise(err)
+	}
+
+	filter, err := gts.Selector(*selector)
+	if err != nil {
+		return ctx.Raise(fmt.Errorf("invalid selector syntax: %v", err))
+	}
+
+	otherFilter, err := gts.Selector(*other)
+	if err != nil {
+		return ctx.Raise(fmt.Errorf("invalid selector syntax: %v", err))
+	}
+
+	d, err := newIODelegate(*seqinPath, *seqoutPath)
+	if err != nil {
+		return ctx.Raise(err)
+	}
+	defer d.Close()
+
+	filetype := seqio.Detect(*seqoutPath)
+	if *format != "" {
+		filetype = seqio.ToFileType(*format)
+	}
+
+	props := gts.Props{}
+	for _, s := range *propstrs {
+		name, value := s, ""
+		if i := strings.IndexByte(s, '='); i >= 0 {
+			name, value = s[:i], s[i+1:]
+		}
+		props.Add(name, value)
+	}
+
+	if !*nocache {
+		data := encodePayload([]tuple{
+			{"command", strings.Join(ctx.Name, "-")},
+			{"version", gts.Version.String()},
+			{"operation", *opname},
+			{"selector", *selector},
+			{"other", *other},
+			{"key", *key},
+			{"qualifiers", *propstrs},
+			{"replace", *replace},
+			{"filetype", filetype},
+		})
+
+		ok, err := d.TryCache(h, data)
+		if ok || err != nil {
+			return ctx.Raise(err)
+		}
+	}
+
+	scanner := seqio.NewAutoScanner(d)
+	buffer := bufio.NewWriter(d)
+	writer := seqio.NewWriter(buffer, filetype)
+
+	for scanner.Scan() {
+		seq := scanner.Value()
+
+		// Locations spanning the origin are normalized into joins.
+		normalize := func(loc gts.Location) gts.Location { return loc }
+		if gts.TopologyOf(seq) == gts.Circular {
+			n := gts.Len(seq)
+			normalize = func(loc gts.Location) gts.Location { return loc.Normalize(n) }
+		}
+
+		ff := seq.Features()
+
+		others := []gts.Location{}
+		for _, f := range ff.Filter(otherFilter) {
+			others = append(others, normalize(f.Loc))
+		}
+
+		gg := ff
+		if *replace {
+			gg = ff.Filter(gts.Not(filter))
+		}
+
+		for _, f := range ff.Filter(filter) {
+			loc := op(normalize(f.Loc), others)
+			if loc == nil {
+				continue
+			}
+
+			k := f.Key
+			if *key != "" {
+				k = *key
+			}
+
+			fprops := f.Props.Clone()
+			for _, item := range props.Items() {
+				fprops.Add(item.Key, item.Value)
+			}
+
+			gg = gg.Insert(gts.NewFeature(k, loc, fprops))
+		}
+
+		seq = gts.WithFeatures(seq, gg)
+
+		if _, err := writer.WriteSeq(seq); err != nil {
+			return ctx.Raise(err)
+		}
+
+		if err := buffer.Flush(); err != nil {
+			return ctx.Raise(err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
+	}
+
+	return ctx.Raise(closeWriter(writer, buffer))
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"bufio"
-	"fmt"
 	"os"
 	"strings"
 
@@ -29,6 +28,7 @@ func complementFunc(ctx *flags.Context) error {
 	nocache := opt.Switch(0, "no-cache", "do not use or create cache")
 	seqoutPath := opt.String('o', "output", "-", "output sequence file (specifying `-` will force standard output)")
 	format := opt.String('F', "format", "", "output file format (defaults to same as input)")
+	jobs := opt.Int('j', "jobs", 1, "number of sequences to process in parallel (0 to use all available CPUs)")
 
 	if err := ctx.Parse(pos, opt); err != nil {
 		return err
@@ -62,21 +62,9 @@ func complementFunc(ctx *flags.Context) error {
 	buffer := bufio.NewWriter(d)
 	writer := seqio.NewWriter(buffer, filetype)
 
-	for scanner.Scan() {
-		seq := scanner.Value()
-		seq = gts.Complement(seq)
-		if _, err := writer.WriteSeq(seq); err != nil {
-			return ctx.Raise(err)
-		}
-	}
-
-	if err := buffer.Flush(); err != nil {
-		return ctx.Raise(err)
-	}
-
-	if err := scanner.Err(); err != nil {
-		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
-	}
+	err = runPipeline(scanner, buffer, writer, *jobs, func(seq gts.Sequence) ([]gts.Sequence, error) {
+		return []gts.Sequence{gts.Complement(seq)}, nil
+	})
 
-	return nil
+	return ctx.Raise(err)
 }
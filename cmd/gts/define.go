@@ -105,5 +105,5 @@ func defineFunc(ctx *flags.Context) error {
 		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
 	}
 
-	return nil
+	return ctx.Raise(closeWriter(writer, buffer))
 }
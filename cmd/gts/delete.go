@@ -101,5 +101,5 @@ func deleteFunc(ctx *flags.Context) error {
 		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
 	}
 
-	return nil
+	return ctx.Raise(closeWriter(writer, buffer))
 }
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-gts/flags"
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/cmd"
+	"github.com/go-gts/gts/seqio"
+)
+
+func init() {
+	flags.Register("diff", "report the differences between two sequences", diffFunc)
+}
+
+type baseEditJSON struct {
+	Type     string `json:"type"`
+	Location string `json:"location"`
+	Position int    `json:"position"`
+	Old      string `json:"old"`
+	New      string `json:"new"`
+}
+
+type qualifierDiffJSON struct {
+	Name string   `json:"name"`
+	Old  []string `json:"old"`
+	New  []string `json:"new"`
+}
+
+type featureDiffJSON struct {
+	Change     string              `json:"change"`
+	Key        string              `json:"key"`
+	Old        string              `json:"old,omitempty"`
+	New        string              `json:"new,omitempty"`
+	Qualifiers []qualifierDiffJSON `json:"qualifiers,omitempty"`
+}
+
+type seqDiffJSON struct {
+	Old      string            `json:"old"`
+	New      string            `json:"new"`
+	Bases    []baseEditJSON    `json:"bases"`
+	Features []featureDiffJSON `json:"features"`
+}
+
+func formatQualifiers(name string, values []string) []string {
+	ss := make([]string, len(values))
+	for i, value := range values {
+		ss[i] = fmt.Sprintf("/%s=%s", name, strconv.Quote(value))
+	}
+	return ss
+}
+
+func writeDiff(w io.Writer, prev, next gts.Sequence, edits []gts.BaseEdit, diffs []gts.FeatureDiff) error {
+	b := strings.Builder{}
+	b.WriteString(fmt.Sprintf("--- %s\n", seqio.SequenceID(prev)))
+	b.WriteString(fmt.Sprintf("+++ %s\n", seqio.SequenceID(next)))
+
+	for _, edit := range edits {
+		switch edit.Type() {
+		case gts.Insertion:
+			b.WriteString(fmt.Sprintf("insertion %s +%s\n", edit.Loc, edit.New))
+		case gts.Deletion:
+			b.WriteString(fmt.Sprintf("deletion %s -%s\n", edit.Loc, edit.Old))
+		default:
+			b.WriteString(fmt.Sprintf("substitution %s %s -> %s\n", edit.Loc, edit.Old, edit.New))
+		}
+	}
+
+	for _, d := range diffs {
+		switch d.Change {
+		case gts.FeatureAdded:
+			b.WriteString(fmt.Sprintf("feature added %s %s\n", d.New.Key, d.New.Loc))
+		case gts.FeatureRemoved:
+			b.WriteString(fmt.Sprintf("feature removed %s %s\n", d.Old.Key, d.Old.Loc))
+		case gts.FeatureMoved:
+			b.WriteString(fmt.Sprintf("feature moved %s %s -> %s\n", d.Old.Key, d.Old.Loc, d.New.Loc))
+		case gts.FeatureModified:
+			b.WriteString(fmt.Sprintf("feature modified %s %s\n", d.Old.Key, d.Old.Loc))
+		}
+		for _, q := range d.Qualifiers {
+			for _, s := range formatQualifiers(q.Name, q.Old) {
+				b.WriteString(fmt.Sprintf("    - %s\n", s))
+			}
+			for _, s := range formatQualifiers(q.Name, q.New) {
+				b.WriteString(fmt.Sprintf("    + %s\n", s))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeDiffJSON(w io.Writer, prev, next gts.Sequence, edits []gts.BaseEdit, diffs []gts.FeatureDiff) error {
+	out := seqDiffJSON{
+		Old:      seqio.SequenceID(prev),
+		New:      seqio.SequenceID(next),
+		Bases:    make([]baseEditJSON, len(edits)),
+		Features: make([]featureDiffJSON, len(diffs)),
+	}
+
+	for i, edit := range edits {
+		out.Bases[i] = baseEditJSON{
+			Type:     edit.Type().String(),
+			Location: edit.Loc.String(),
+			Position: edit.Pos + 1,
+			Old:      string(edit.Old),
+			New:      string(edit.New),
+		}
+	}
+
+	for i, d := range diffs {
+		f := featureDiffJSON{Change: d.Change.String(), Key: d.New.Key}
+		if d.Change != gts.FeatureAdded {
+			f.Key, f.Old = d.Old.Key, d.Old.Loc.String()
+		}
+		if d.Change != gts.FeatureRemoved {
+			f.New = d.New.Loc.String()
+		}
+		for _, q := range d.Qualifiers {
+			f.Qualifiers = append(f.Qualifiers, qualifierDiffJSON{q.Name, q.Old, q.New})
+		}
+		out.Features[i] = f
+	}
+
+	p, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(p, '\n'))
+	return err
+}
+
+func diffFunc(ctx *flags.Context) error {
+	pos, opt := flags.Flags()
+
+	oldPath := pos.String("old", "old sequence file to compare against")
+
+	var seqinPath *string
+	if cmd.IsTerminal(os.Stdin.Fd()) {
+		seqinPath = pos.String("seqin", "input sequence file (may be omitted if standard input is provided)")
+	}
+
+	outPath := opt.String('o', "output", "-", "output file (specifying `-` will force standard output)")
+	jsonFlag := opt.Switch('j', "json", "report the differences in JSON format")
+
+	if err := ctx.Parse(pos, opt); err != nil {
+		return err
+	}
+
+	oldFile, err := openDecompressed(*oldPath)
+	if err != nil {
+		return ctx.Raise(fmt.Errorf("failed to open file %q: %v", *oldPath, err))
+	}
+	defer oldFile.Close()
+
+	seqinFile := os.Stdin
+	if seqinPath != nil && *seqinPath != "-" {
+		f, err := os.Open(*seqinPath)
+		if err != nil {
+			return ctx.Raise(fmt.Errorf("failed to open file %q: %v", *seqinPath, err))
+		}
+		seqinFile = f
+		defer seqinFile.Close()
+	}
+
+	outFile := os.Stdout
+	if *outPath != "-" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			return ctx.Raise(fmt.Errorf("failed to create file %q: %v", *outPath, err))
+		}
+		outFile = f
+		defer outFile.Close()
+	}
+
+	write := writeDiff
+	if *jsonFlag {
+		write = writeDiffJSON
+	}
+
+	w := bufio.NewWriter(outFile)
+
+	seqinReader, err := seqio.NewDecompressor(seqinFile)
+	if err != nil {
+		return ctx.Raise(err)
+	}
+
+	oldScanner := seqio.NewAutoScanner(oldFile)
+	scanner := seqio.NewAutoScanner(seqinReader)
+	for scanner.Scan() {
+		if !oldScanner.Scan() {
+			if err := oldScanner.Err(); err != nil {
+				return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
+			}
+			return ctx.Raise(fmt.Errorf("old sequence file %q contains fewer sequences than the input", *oldPath))
+		}
+
+		prev, next := oldScanner.Value(), scanner.Value()
+		edits := gts.DiffBases(prev, next)
+		diffs := gts.DiffFeatures(prev.Features(), next.Features(), edits)
+
+		if err := write(w, prev, next, edits, diffs); err != nil {
+			return ctx.Raise(err)
+		}
+
+		if err := w.Flush(); err != nil {
+			return ctx.Raise(err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
+	}
+
+	if oldScanner.Scan() {
+		return ctx.Raise(fmt.Errorf("old sequence file %q contains more sequences than the input", *oldPath))
+	}
+
+	return nil
+}
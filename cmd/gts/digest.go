@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-gts/flags"
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/cmd"
+	"github.com/go-gts/gts/seqio"
+)
+
+func init() {
+	flags.Register("digest", "simulate a restriction enzyme digest", digestFunc)
+}
+
+func digestFunc(ctx *flags.Context) error {
+	h := newHash()
+	pos, opt := flags.Flags()
+
+	enzymestr := pos.String("enzymes", "comma separated list of restriction enzyme names")
+
+	seqinPath := new(string)
+	*seqinPath = "-"
+	if cmd.IsTerminal(os.Stdin.Fd()) {
+		seqinPath = pos.String("seqin", "input sequence file (may be omitted if standard input is provided)")
+	}
+
+	nocache := opt.Switch(0, "no-cache", "do not use or create cache")
+	seqoutPath := opt.String('o', "output", "-", "output sequence file (specifying `-` will force standard output)")
+	format := opt.String('F', "format", "", "output file format (defaults to same as input)")
+	featureKey := opt.String('k', "key", "misc_feature", "key for the reported restriction site features")
+	propstrs := opt.StringSlice('q', "qualifier", nil, "qualifier key-value pairs (syntax: key=value))")
+	fragments := opt.Switch('f', "fragments", "split the sequence into fragments instead of annotating the sites")
+
+	if err := ctx.Parse(pos, opt); err != nil {
+		return err
+	}
+
+	enzymes := []gts.Enzyme{}
+	for _, name := range strings.Split(*enzymestr, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		e, err := gts.LookupEnzyme(name)
+		if err != nil {
+			return ctx.Raise(err)
+		}
+		enzymes = append(enzymes, e)
+	}
+
+	if len(enzymes) == 0 {
+		return ctx.Raise(fmt.Errorf("no restriction enzymes given"))
+	}
+
+	d, err := newIODelegate(*seqinPath, *seqoutPath)
+	if err != nil {
+		return ctx.Raise(err)
+	}
+	defer d.Close()
+
+	filetype := seqio.Detect(*seqoutPath)
+	if *format != "" {
+		filetype = seqio.ToFileType(*format)
+	}
+
+	props := gts.Props{}
+	for _, s := range *propstrs {
+		name, value := s, ""
+		if i := strings.IndexByte(s, '='); i >= 0 {
+			name, value = s[:i], s[i+1:]
+		}
+		props.Add(name, value)
+	}
+
+	if !*nocache {
+		data := encodePayload([]tuple{
+			{"command", strings.Join(ctx.Name, "-")},
+			{"version", gts.Version.String()},
+			{"enzymes", enzymes},
+			{"filetype", filetype},
+			{"featureKey", *featureKey},
+			{"propstrs", *propstrs},
+			{"fragments", *fragments},
+		})
+
+		ok, err := d.TryCache(h, data)
+		if ok || err != nil {
+			return ctx.Raise(err)
+		}
+	}
+
+	scanner := seqio.NewAutoScanner(d)
+	buffer := bufio.NewWriter(d)
+	writer := seqio.NewWriter(buffer, filetype)
+
+	for scanner.Scan() {
+		seq := scanner.Value()
+
+		if *fragments {
+			for _, frag := range gts.Digest(seq, enzymes...) {
+				if _, err := writer.WriteSeq(frag); err != nil {
+					return ctx.Raise(err)
+				}
+			}
+		} else {
+			ff := seq.Features()
+			for _, site := range gts.FindRestrictionSites(seq, enzymes...) {
+				fprops := props.Clone()
+				fprops.Add("note", site.Enzyme.Name)
+				ff = ff.Insert(gts.NewFeature(*featureKey, site.Loc, fprops))
+			}
+			seq = gts.WithFeatures(seq, ff)
+			if _, err := writer.WriteSeq(seq); err != nil {
+				return ctx.Raise(err)
+			}
+		}
+
+		if err := buffer.Flush(); err != nil {
+			return ctx.Raise(err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
+	}
+
+	return ctx.Raise(closeWriter(writer, buffer))
+}
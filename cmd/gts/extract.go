@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"hash"
 	"os"
 	"reflect"
 	"strings"
@@ -26,6 +27,138 @@ func containsRegion(rr []gts.Region, r gts.Region) bool {
 	return false
 }
 
+func containsSegment(ss []gts.Segment, s gts.Segment) bool {
+	for i := range ss {
+		if ss[i] == s {
+			return true
+		}
+	}
+	return false
+}
+
+// rangeRegions returns the regions of the locators if all of them are ranges.
+func rangeRegions(locstrs []string) ([]gts.Segment, bool) {
+	rr := []gts.Segment{}
+	for _, locstr := range locstrs {
+		if _, err := gts.AsModifier(locstr); err == nil {
+			return nil, false
+		}
+		loc, err := gts.AsLocation(locstr)
+		if err != nil {
+			return nil, false
+		}
+		ranged, ok := loc.(gts.Ranged)
+		if !ok {
+			return nil, false
+		}
+		seg := gts.Segment{ranged.Start, ranged.End}
+		if !containsSegment(rr, seg) {
+			rr = append(rr, seg)
+		}
+	}
+	return rr, true
+}
+
+// loadRemotes reads the sequences in the given file and returns a Resolver
+// which looks up the sequences by their identifiers.
+func loadRemotes(path string, h hash.Hash) (gts.Resolver, error) {
+	f, err := openDecompressed(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %q: %v", path, err)
+	}
+	defer f.Close()
+
+	remotes := make(map[string]gts.Sequence)
+	scanner := seqio.NewAutoScanner(attach(h, f))
+	for scanner.Scan() {
+		seq := scanner.Value()
+		for _, id := range sequenceIDs(seq) {
+			if _, ok := remotes[id]; !ok && id != "" {
+				remotes[id] = seq
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read file: %q: %v", path, err)
+	}
+
+	return func(accession string) (gts.Sequence, bool) {
+		seq, ok := remotes[accession]
+		return seq, ok
+	}, nil
+}
+
+// extractFaidx writes the regions of each sequence in an indexed FASTA file.
+func extractFaidx(f *os.File, index seqio.FaidxIndex, rr []gts.Segment, writer seqio.SeqWriter) error {
+	for _, e := range index {
+		desc, err := e.ReadDesc(f)
+		if err != nil {
+			return err
+		}
+		for _, region := range rr {
+			head, tail := gts.Unpack(region)
+			if len(rr) == 1 || tail-head != e.Length {
+				p, err := e.ReadRegion(f, head, tail)
+				if err != nil {
+					return err
+				}
+				if _, err := writer.WriteSeq(seqio.Fasta{Desc: desc, Data: p}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// extractRegions returns the sequences of the regions referenced by the
+// locators in the given sequence. Remote locations are resolved using the
+// given Resolver if it is not nil.
+func extractRegions(seq gts.Sequence, locators []gts.Locator, invert bool, resolve gts.Resolver) ([]gts.Sequence, error) {
+	if resolve != nil {
+		seq = gts.WithResolver(seq, resolve)
+	}
+
+	rr := make([]gts.Region, 0)
+	for _, locate := range locators {
+		for _, r := range locate(seq) {
+			if !containsRegion(rr, r) {
+				rr = append(rr, r)
+			}
+		}
+	}
+
+	if invert {
+		// Support linear inversion only as topology is not well defined.
+		rr = gts.InvertLinear(gts.Regions(rr), gts.Len(seq))
+	}
+
+	// Index the features as the sequence may be sliced many times.
+	if len(rr) > 1 {
+		seq = gts.IndexFeatures(seq)
+	}
+
+	for _, region := range rr {
+		if accessions := gts.Unresolved(seq, region); len(accessions) > 0 {
+			return nil, fmt.Errorf("cannot resolve remote entries referenced in %q: %s (use --remote to provide the entries)", seqio.SequenceID(seq), strings.Join(accessions, ", "))
+		}
+	}
+
+	seqs := []gts.Sequence{}
+	for _, region := range rr {
+		if len(rr) == 1 || region.Len() != gts.Len(seq) {
+			out := region.Locate(seq)
+			// Drop the resolver so that the output format is detected from
+			// the record itself.
+			if resolved, ok := out.(gts.ResolvedSequence); ok {
+				out = resolved.Sequence
+			}
+			seqs = append(seqs, out)
+		}
+	}
+	return seqs, nil
+}
+
 func extractFunc(ctx *flags.Context) error {
 	h := newHash()
 	pos, opt := flags.Flags()
@@ -42,11 +175,24 @@ func extractFunc(ctx *flags.Context) error {
 	format := opt.String('F', "format", "", "output file format (defaults to same as input)")
 	seqoutPath := opt.String('o', "output", "-", "output sequence file (specifying `-` will force standard output)")
 	invert := opt.Switch('v', "invert-region", "extract the sequences that are not referenced by the features")
+	jobs := opt.Int('j', "jobs", 1, "number of sequences to process in parallel (0 to use all available CPUs)")
+	remotePath := opt.String(0, "remote", "", "sequence file containing the entries referenced by remote locations")
 
 	if err := ctx.Parse(pos, opt); err != nil {
 		return err
 	}
 
+	var resolve gts.Resolver
+	h.Reset()
+	if *remotePath != "" {
+		r, err := loadRemotes(*remotePath, h)
+		if err != nil {
+			return ctx.Raise(err)
+		}
+		resolve = r
+	}
+	remoteSum := h.Sum(nil)
+
 	d, err := newIODelegate(*seqinPath, *seqoutPath)
 	if err != nil {
 		return ctx.Raise(err)
@@ -72,11 +218,27 @@ func extractFunc(ctx *flags.Context) error {
 		locators[i] = locator
 	}
 
+	// Read the regions directly from the file if the input is an indexed FASTA
+	// file and all of the locators are ranges.
+	if *seqinPath != "-" && !*invert {
+		if index := loadFaidx(*seqinPath, d.infile); index != nil {
+			if rr, ok := rangeRegions(*locstrs); ok {
+				buffer := bufio.NewWriter(d)
+				writer := seqio.NewWriter(buffer, filetype)
+				if err := extractFaidx(d.infile, index, rr, writer); err != nil {
+					return ctx.Raise(err)
+				}
+				return ctx.Raise(closeWriter(writer, buffer))
+			}
+		}
+	}
+
 	if !*nocache {
 		data := encodePayload([]tuple{
 			{"command", strings.Join(ctx.Name, "-")},
 			{"version", gts.Version.String()},
 			{"locators", *locstrs},
+			{"remote", remoteSum},
 			{"filetype", filetype},
 		})
 
@@ -90,39 +252,9 @@ func extractFunc(ctx *flags.Context) error {
 	buffer := bufio.NewWriter(d)
 	writer := seqio.NewWriter(buffer, filetype)
 
-	for scanner.Scan() {
-		seq := scanner.Value()
-
-		rr := make([]gts.Region, 0)
-		for _, locate := range locators {
-			for _, r := range locate(seq) {
-				if !containsRegion(rr, r) {
-					rr = append(rr, r)
-				}
-			}
-		}
-
-		if *invert {
-			// Support linear inversion only as topology is not well defined.
-			rr = gts.InvertLinear(gts.Regions(rr), gts.Len(seq))
-		}
-
-		for _, region := range rr {
-			if len(rr) == 1 || region.Len() != gts.Len(seq) {
-				out := region.Locate(seq)
-				if _, err := writer.WriteSeq(out); err != nil {
-					return ctx.Raise(err)
-				}
-				if err := buffer.Flush(); err != nil {
-					return ctx.Raise(err)
-				}
-			}
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
-	}
+	err = runPipeline(scanner, buffer, writer, *jobs, func(seq gts.Sequence) ([]gts.Sequence, error) {
+		return extractRegions(seq, locators, *invert, resolve)
+	})
 
-	return nil
+	return ctx.Raise(err)
 }
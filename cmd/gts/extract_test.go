@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/internal/testutils"
+	"github.com/go-gts/gts/seqio"
+)
+
+func writeAuto(t *testing.T, seqs []gts.Sequence) string {
+	t.Helper()
+	b := strings.Builder{}
+	writer := seqio.NewWriter(&b, seqio.DefaultFile)
+	for _, seq := range seqs {
+		if _, err := writer.WriteSeq(seq); err != nil {
+			t.Fatalf("writer.WriteSeq(seq): %v", err)
+		}
+	}
+	return b.String()
+}
+
+func TestExtractRegionsRemote(t *testing.T) {
+	remote := seqio.Fasta{Desc: "J00194.1", Data: []byte("atgcatgcatgc")}
+	resolve := func(accession string) (gts.Sequence, bool) {
+		return remote, accession == "J00194.1"
+	}
+
+	locators := []gts.Locator{}
+	for _, locstr := range []string{"1..10", "21..30"} {
+		locator, err := gts.AsLocator(locstr)
+		if err != nil {
+			t.Fatalf("gts.AsLocator(%q): %v", locstr, err)
+		}
+		locators = append(locators, locator)
+	}
+
+	prefixes := map[string]string{
+		"NC_001422.embl":  "ID   ",
+		"NC_001422.fasta": ">",
+		"NC_001422.gb":    "LOCUS",
+	}
+
+	for filename, prefix := range prefixes {
+		in := testutils.ReadTestfilePkg(t, filename, "../../seqio")
+		scanner := seqio.NewAutoScanner(strings.NewReader(in))
+		if !scanner.Scan() {
+			t.Fatalf("%s: scanner.Err() = %v", filename, scanner.Err())
+		}
+		seq := scanner.Value()
+
+		for n := 1; n <= len(locators); n++ {
+			exp, err := extractRegions(seq, locators[:n], false, nil)
+			if err != nil {
+				t.Fatalf("%s: extractRegions(seq, locators, false, nil): %v", filename, err)
+			}
+			out, err := extractRegions(seq, locators[:n], false, resolve)
+			if err != nil {
+				t.Fatalf("%s: extractRegions(seq, locators, false, resolve): %v", filename, err)
+			}
+
+			// The output format is detected from the record with or without
+			// a resolver.
+			s := writeAuto(t, out)
+			if !strings.HasPrefix(s, prefix) {
+				t.Errorf("%s: output starts with %q, want %q", filename, strings.SplitN(s, "\n", 2)[0], prefix)
+			}
+			testutils.DiffLine(t, writeAuto(t, exp), s)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/go-gts/flags"
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/cmd"
+	"github.com/go-gts/gts/seqio"
+)
+
+func init() {
+	flags.Register("fetch", "fetch sequence(s) by ID using an index", fetchFunc)
+}
+
+// fetchAll scans the entire input for the sequences with the given IDs.
+func fetchAll(f *os.File, ids []string) (map[string]gts.Sequence, error) {
+	seqs := make(map[string]gts.Sequence)
+	for _, id := range ids {
+		seqs[id] = nil
+	}
+
+	r, err := seqio.NewDecompressor(f)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := seqio.NewAutoScanner(r)
+	for scanner.Scan() {
+		seq := scanner.Value()
+		id := seqio.SequenceID(seq)
+		if prev, ok := seqs[id]; ok && prev == nil {
+			seqs[id] = seq
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("encountered error in scanner: %v", err)
+	}
+
+	return seqs, nil
+}
+
+func fetchFunc(ctx *flags.Context) error {
+	pos, opt := flags.Flags()
+
+	ids := pos.Extra("id", "ID of the sequence to fetch")
+
+	seqinPath := new(string)
+	*seqinPath = "-"
+	if cmd.IsTerminal(os.Stdin.Fd()) {
+		seqinPath = pos.String("seqin", "input sequence file (may be omitted if standard input is provided)")
+	}
+
+	seqoutPath := opt.String('o', "output", "-", "output sequence file (specifying `-` will force standard output)")
+	format := opt.String('F', "format", "", "output file format (defaults to same as input)")
+	indexFlag := opt.String('i', "index", "", "index file to use (defaults to the input filename suffixed with `.gtsi`)")
+
+	if err := ctx.Parse(pos, opt); err != nil {
+		return err
+	}
+
+	if *indexFlag == "" && *seqinPath != "-" {
+		*indexFlag = indexPath(*seqinPath)
+	}
+
+	seqinFile := os.Stdin
+	if *seqinPath != "-" {
+		f, err := os.Open(*seqinPath)
+		if err != nil {
+			return ctx.Raise(fmt.Errorf("failed to open file %q: %v", *seqinPath, err))
+		}
+		seqinFile = f
+		defer seqinFile.Close()
+	}
+
+	seqoutFile := os.Stdout
+	if *seqoutPath != "-" {
+		f, err := os.Create(*seqoutPath)
+		if err != nil {
+			return ctx.Raise(fmt.Errorf("failed to create file %q: %v", *seqoutPath, err))
+		}
+		seqoutFile = f
+		defer seqoutFile.Close()
+	}
+
+	seqoutWriter, err := seqio.NewCompressor(seqoutFile, seqio.DetectCompression(*seqoutPath))
+	if err != nil {
+		return ctx.Raise(err)
+	}
+
+	filetype := seqio.Detect(*seqoutPath)
+	if *format != "" {
+		filetype = seqio.ToFileType(*format)
+	}
+
+	fetch := func(id string) (gts.Sequence, error) {
+		return nil, nil
+	}
+
+	if index := loadIndex(*indexFlag, seqinFile); index != nil {
+		fetch = func(id string) (gts.Sequence, error) {
+			entry, ok := index.Lookup(id)
+			if !ok {
+				return nil, nil
+			}
+			return seqio.ReadRecord(seqinFile, entry)
+		}
+	} else {
+		// Fall back to scanning the input if an index is not available.
+		seqs, err := fetchAll(seqinFile, *ids)
+		if err != nil {
+			return ctx.Raise(err)
+		}
+		fetch = func(id string) (gts.Sequence, error) {
+			return seqs[id], nil
+		}
+	}
+
+	buffer := bufio.NewWriter(seqoutWriter)
+	writer := seqio.NewWriter(buffer, filetype)
+
+	for _, id := range *ids {
+		seq, err := fetch(id)
+		if err != nil {
+			return ctx.Raise(fmt.Errorf("failed to read sequence %q: %v", id, err))
+		}
+		if seq == nil {
+			return ctx.Raise(fmt.Errorf("sequence %q not found", id))
+		}
+
+		if _, err := writer.WriteSeq(seq); err != nil {
+			return ctx.Raise(err)
+		}
+
+		if err := buffer.Flush(); err != nil {
+			return ctx.Raise(err)
+		}
+	}
+
+	if err := closeWriter(writer, buffer); err != nil {
+		return ctx.Raise(err)
+	}
+
+	return ctx.Raise(seqoutWriter.Close())
+}
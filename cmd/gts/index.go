@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-gts/flags"
+	"github.com/go-gts/gts/seqio"
+)
+
+func init() {
+	flags.Register("index", "build an index for random access to the sequences", indexFunc)
+}
+
+// indexPath returns the default path of the index file for the given file.
+func indexPath(path string) string {
+	return path + ".gtsi"
+}
+
+// loadIndex reads the index file at the given path. If the index cannot be
+// read or does not match the size of the given file, nil will be returned.
+func loadIndex(path string, f *os.File) *seqio.Index {
+	indexFile, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer indexFile.Close()
+
+	index, err := seqio.ReadIndex(indexFile)
+	if err != nil {
+		return nil
+	}
+
+	info, err := f.Stat()
+	if err != nil || info.Size() != index.Size {
+		return nil
+	}
+
+	return index
+}
+
+// loadFaidx reads the samtools compatible FASTA index for the given file. If
+// the index cannot be read or refers beyond the end of the file, nil will be
+// returned.
+func loadFaidx(path string, f *os.File) seqio.FaidxIndex {
+	faiFile, err := os.Open(path + ".fai")
+	if err != nil {
+		return nil
+	}
+	defer faiFile.Close()
+
+	index, err := seqio.ReadFaidx(faiFile)
+	if err != nil || len(index) == 0 {
+		return nil
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil
+	}
+
+	for _, e := range index {
+		lines := int64(0)
+		if e.LineBases > 0 {
+			lines = int64((e.Length - 1) / e.LineBases)
+		}
+		if info.Size() < e.Offset+lines*int64(e.LineWidth) {
+			return nil
+		}
+	}
+
+	return index
+}
+
+func indexFunc(ctx *flags.Context) error {
+	pos, opt := flags.Flags()
+
+	seqinPath := pos.String("seqin", "input sequence file")
+
+	outPath := opt.String('o', "output", "", "output index file (defaults to the input filename suffixed with `.gtsi` or `.fai`)")
+	faidx := opt.Switch('f', "faidx", "build a samtools compatible FASTA index instead")
+
+	if err := ctx.Parse(pos, opt); err != nil {
+		return err
+	}
+
+	if *outPath == "" {
+		*outPath = indexPath(*seqinPath)
+		if *faidx {
+			*outPath = *seqinPath + ".fai"
+		}
+	}
+
+	seqinFile, err := os.Open(*seqinPath)
+	if err != nil {
+		return ctx.Raise(fmt.Errorf("failed to open file %q: %v", *seqinPath, err))
+	}
+	defer seqinFile.Close()
+
+	var index io.WriterTo
+	if *faidx {
+		index, err = seqio.BuildFaidx(seqinFile)
+	} else {
+		index, err = seqio.BuildIndex(seqinFile)
+	}
+	if err != nil {
+		return ctx.Raise(err)
+	}
+
+	outFile := os.Stdout
+	if *outPath != "-" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			return ctx.Raise(fmt.Errorf("failed to create file %q: %v", *outPath, err))
+		}
+		outFile = f
+		defer outFile.Close()
+	}
+
+	w := bufio.NewWriter(outFile)
+	if _, err := index.WriteTo(w); err != nil {
+		return ctx.Raise(err)
+	}
+
+	return ctx.Raise(w.Flush())
+}
@@ -46,7 +46,7 @@ func infixFunc(ctx *flags.Context) error {
 
 	hosts := []gts.Sequence{}
 
-	f, err := os.Open(*hostPath)
+	f, err := openDecompressed(*hostPath)
 	if err != nil {
 		return ctx.Raise(fmt.Errorf("failed to open file: %q: %v", *hostPath, err))
 	}
@@ -129,5 +129,5 @@ func infixFunc(ctx *flags.Context) error {
 		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
 	}
 
-	return nil
+	return ctx.Raise(closeWriter(writer, buffer))
 }
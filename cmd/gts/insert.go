@@ -55,7 +55,7 @@ func insertFunc(ctx *flags.Context) error {
 		guests = append(guests, guest)
 
 	default:
-		f, err := os.Open(*guestPath)
+		f, err := openDecompressed(*guestPath)
 		if err != nil {
 			return ctx.Raise(fmt.Errorf("failed to open file: %q: %v", *guestPath, err))
 		}
@@ -138,5 +138,5 @@ func insertFunc(ctx *flags.Context) error {
 		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
 	}
 
-	return nil
+	return ctx.Raise(closeWriter(writer, buffer))
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"compress/flate"
 	"encoding/json"
 	"hash"
@@ -10,6 +11,7 @@ import (
 	"path/filepath"
 
 	"github.com/go-gts/gts/cmd/cache"
+	"github.com/go-gts/gts/seqio"
 )
 
 type attachment struct {
@@ -18,17 +20,32 @@ type attachment struct {
 }
 
 func (a *attachment) Read(p []byte) (int, error) {
+	// A reader may return the final bytes along with io.EOF, as the gzip
+	// reader does, so the bytes must be written before the error is checked.
 	n, err := a.r.Read(p)
-	if err != nil {
-		return 0, err
+	if n > 0 {
+		if m, werr := a.w.Write(p[:n]); werr != nil {
+			return m, werr
+		}
 	}
-	return a.w.Write(p[:n])
+	return n, err
 }
 
 func attach(w io.Writer, r io.Reader) *attachment {
 	return &attachment{r, w}
 }
 
+// closeWriter terminates the output of the sequence writer if necessary, as
+// in the case of a JSON array, and flushes the buffer.
+func closeWriter(writer seqio.SeqWriter, buffer *bufio.Writer) error {
+	if c, ok := writer.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			return err
+		}
+	}
+	return buffer.Flush()
+}
+
 type tuple [2]interface{}
 
 func encodePayload(tt []tuple) []byte {
@@ -48,9 +65,37 @@ func gtsCacheDir() (string, error) {
 	return dir, os.MkdirAll(dir, 0755)
 }
 
+// decompressedFile is a file whose content is decompressed as it is read.
+type decompressedFile struct {
+	io.ReadCloser
+	file *os.File
+}
+
+func (f decompressedFile) Close() error {
+	f.ReadCloser.Close()
+	return f.file.Close()
+}
+
+// openDecompressed opens the named file for reading, decompressing the
+// content if the file is compressed.
+func openDecompressed(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := seqio.NewDecompressor(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return decompressedFile{r, f}, nil
+}
+
 type ioDelegate struct {
 	infile  *os.File
 	outfile *os.File
+	reader  io.ReadCloser
+	writer  io.WriteCloser
 	cache   *cache.File
 	tmpin   bool
 }
@@ -71,11 +116,26 @@ func newIODelegate(inpath, outpath string) (*ioDelegate, error) {
 		}
 	}
 
-	return &ioDelegate{input, output, nil, false}, nil
+	writer, err := seqio.NewCompressor(output, seqio.DetectCompression(outpath))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ioDelegate{input, output, nil, writer, nil, false}, nil
 }
 
+// Read reads the input, decompressing it if it is a compressed stream. The
+// decompressor is created on the first read so that the input may be swapped
+// out for a temporary file by TryCache beforehand.
 func (d *ioDelegate) Read(p []byte) (int, error) {
-	return d.infile.Read(p)
+	if d.reader == nil {
+		r, err := seqio.NewDecompressor(d.infile)
+		if err != nil {
+			return 0, err
+		}
+		d.reader = r
+	}
+	return d.reader.Read(p)
 }
 
 func (d *ioDelegate) Write(p []byte) (int, error) {
@@ -85,7 +145,7 @@ func (d *ioDelegate) Write(p []byte) (int, error) {
 			return n, err
 		}
 	}
-	n, err := d.outfile.Write(p)
+	n, err := d.writer.Write(p)
 	return n, err
 }
 
@@ -116,9 +176,15 @@ func (d *ioDelegate) TryCache(h hash.Hash, data []byte) (bool, error) {
 		d.tmpin = true
 	}
 
-	// Compute the root file and data hash sums.
+	// Compute the root file and data hash sums. The root file is hashed after
+	// decompression so that compressed and plain inputs share the cache.
 	h.Reset()
-	if _, err := io.Copy(h, d.infile); err != nil {
+	r, err := seqio.NewDecompressor(d.infile)
+	if err == nil {
+		_, err = io.Copy(h, r)
+		r.Close()
+	}
+	if err != nil {
 		if _, err := d.infile.Seek(0, io.SeekStart); err != nil {
 			return false, err
 		}
@@ -147,7 +213,7 @@ func (d *ioDelegate) TryCache(h hash.Hash, data []byte) (bool, error) {
 
 	defer f.Close()
 
-	if _, err := io.Copy(d.outfile, f); err != nil {
+	if _, err := io.Copy(d.writer, f); err != nil {
 		return false, nil
 	}
 
@@ -166,11 +232,15 @@ func (d *ioDelegate) Close() error {
 	defer d.infile.Close()
 	defer d.outfile.Close()
 
+	if d.reader != nil {
+		d.reader.Close()
+	}
+
 	if d.cache != nil {
 		if err := d.cache.Close(); err != nil {
 			os.Remove(d.cache.Name())
 		}
 	}
 
-	return nil
+	return d.writer.Close()
 }
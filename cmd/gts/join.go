@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"strings"
@@ -72,7 +73,8 @@ func joinFunc(ctx *flags.Context) error {
 		seq = gts.WithTopology(seq, gts.Circular)
 	}
 
-	writer := seqio.NewWriter(d, filetype)
+	buffer := bufio.NewWriter(d)
+	writer := seqio.NewWriter(buffer, filetype)
 
 	if _, err := writer.WriteSeq(seq); err != nil {
 		return ctx.Raise(err)
@@ -82,5 +84,5 @@ func joinFunc(ctx *flags.Context) error {
 		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
 	}
 
-	return nil
+	return ctx.Raise(closeWriter(writer, buffer))
 }
@@ -52,7 +52,12 @@ func lengthFunc(ctx *flags.Context) error {
 
 	w := bufio.NewWriter(outFile)
 
-	scanner := seqio.NewAutoScanner(seqinFile)
+	seqinReader, err := seqio.NewDecompressor(seqinFile)
+	if err != nil {
+		return ctx.Raise(err)
+	}
+
+	scanner := seqio.NewAutoScanner(seqinReader)
 	for scanner.Scan() {
 		seq := scanner.Value()
 		_, err := io.WriteString(w, fmt.Sprintf("%d\n", gts.Len(seq)))
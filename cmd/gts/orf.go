@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-gts/flags"
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/cmd"
+	"github.com/go-gts/gts/seqio"
+)
+
+func init() {
+	flags.Register("orf", "find open reading frames and annotate them", orfFunc)
+}
+
+func orfFunc(ctx *flags.Context) error {
+	h := newHash()
+	pos, opt := flags.Flags()
+
+	seqinPath := new(string)
+	*seqinPath = "-"
+	if cmd.IsTerminal(os.Stdin.Fd()) {
+		seqinPath = pos.String("seqin", "input sequence file (may be omitted if standard input is provided)")
+	}
+
+	nocache := opt.Switch(0, "no-cache", "do not use or create cache")
+	seqoutPath := opt.String('o', "output", "-", "output sequence file (specifying `-` will force standard output)")
+	format := opt.String('F', "format", "", "output file format (defaults to same as input)")
+	featureKey := opt.String('k', "key", "CDS", "key for the reported open reading frame features")
+	propstrs := opt.StringSlice('q', "qualifier", nil, "qualifier key-value pairs (syntax: key=value))")
+	minLength := opt.Int('m', "min-length", 75, "minimum length of the open reading frames in bases (including the stop codon)")
+	start := opt.String('s', "start", "atg", "start codons to use (`atg`, `table`, or `any`)")
+	table := opt.Int('t', "table", 1, "genetic code table to use")
+	nocomplement := opt.Switch(0, "no-complement", "do not search the complement strand")
+
+	if err := ctx.Parse(pos, opt); err != nil {
+		return err
+	}
+
+	code, ok := gts.GeneticCodes[*table]
+	if !ok {
+		return ctx.Raise(fmt.Errorf("unknown genetic code table: %d", *table))
+	}
+
+	switch *start {
+	case "atg":
+		code.Starts = strings.Repeat("-", 35) + "M" + strings.Repeat("-", 28)
+	case "table":
+	case "any":
+		code.Starts = strings.Repeat("M", 64)
+	default:
+		return ctx.Raise(fmt.Errorf("unknown start codon option: %q", *start))
+	}
+
+	d, err := newIODelegate(*seqinPath, *seqoutPath)
+	if err != nil {
+		return ctx.Raise(err)
+	}
+	defer d.Close()
+
+	filetype := seqio.Detect(*seqoutPath)
+	if *format != "" {
+		filetype = seqio.ToFileType(*format)
+	}
+
+	props := gts.Props{}
+	for _, s := range *propstrs {
+		name, value := s, ""
+		if i := strings.IndexByte(s, '='); i >= 0 {
+			name, value = s[:i], s[i+1:]
+		}
+		props.Add(name, value)
+	}
+	if *featureKey == "CDS" && *table != 1 && props.Get("transl_table") == nil {
+		props.Add("transl_table", strconv.Itoa(*table))
+	}
+
+	if !*nocache {
+		data := encodePayload([]tuple{
+			{"command", strings.Join(ctx.Name, "-")},
+			{"version", gts.Version.String()},
+			{"filetype", filetype},
+			{"featureKey", *featureKey},
+			{"propstrs", *propstrs},
+			{"minLength", *minLength},
+			{"start", *start},
+			{"table", *table},
+			{"nocomplement", *nocomplement},
+		})
+
+		ok, err := d.TryCache(h, data)
+		if ok || err != nil {
+			return ctx.Raise(err)
+		}
+	}
+
+	scanner := seqio.NewAutoScanner(d)
+	buffer := bufio.NewWriter(d)
+	writer := seqio.NewWriter(buffer, filetype)
+
+	for scanner.Scan() {
+		seq := scanner.Value()
+
+		top := gts.TopologyOf(seq)
+
+		ff := seq.Features()
+		for _, loc := range gts.FindORFs(seq, code, top, *minLength) {
+			ff = ff.Insert(gts.NewFeature(*featureKey, loc, props.Clone()))
+		}
+		if !*nocomplement {
+			cmp := gts.Reverse(gts.Complement(gts.New(nil, nil, seq.Bytes())))
+			for _, loc := range gts.FindORFs(cmp, code, top, *minLength) {
+				loc = loc.Reverse(gts.Len(seq)).Complement()
+				ff = ff.Insert(gts.NewFeature(*featureKey, loc, props.Clone()))
+			}
+		}
+
+		seq = gts.WithFeatures(seq, ff)
+		if _, err := writer.WriteSeq(seq); err != nil {
+			return ctx.Raise(err)
+		}
+
+		if err := buffer.Flush(); err != nil {
+			return ctx.Raise(err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
+	}
+
+	return ctx.Raise(closeWriter(writer, buffer))
+}
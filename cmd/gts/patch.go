@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-gts/flags"
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/cmd"
+	"github.com/go-gts/gts/seqio"
+	"github.com/go-pars/pars"
+)
+
+func init() {
+	flags.Register("patch", "apply variants from a VCF file to the sequence(s)", patchFunc)
+}
+
+func featureName(f gts.Feature) string {
+	for _, name := range []string{"gene", "locus_tag", "product"} {
+		if values := f.Props.Get(name); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return "."
+}
+
+func variantFilter(v gts.Variant) gts.Filter {
+	v = v.Trim()
+	if len(v.Ref) == 0 {
+		return gts.And(gts.Overlap(v.Pos-1, v.Pos), gts.Overlap(v.Pos, v.Pos+1))
+	}
+	return gts.Overlap(v.Pos, v.Pos+len(v.Ref))
+}
+
+// reportVariants writes the variants found within the CDS features of the
+// sequence to the report.
+func reportVariants(w io.Writer, seq gts.Sequence, variants []gts.Variant) error {
+	id := seqio.SequenceID(seq)
+	cds := seq.Features().Filter(gts.Key("CDS"))
+	for _, v := range variants {
+		for _, f := range cds.Filter(variantFilter(v)) {
+			line := fmt.Sprintf(
+				"%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+				id, v.Pos+1, v.Ref, v.Alt, v.ID, f.Loc, featureName(f),
+			)
+			if _, err := io.WriteString(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func patchFunc(ctx *flags.Context) error {
+	h := newHash()
+	pos, opt := flags.Flags()
+
+	vcfPath := pos.String("vcf", "VCF file containing the variants to apply")
+
+	seqinPath := new(string)
+	*seqinPath = "-"
+	if cmd.IsTerminal(os.Stdin.Fd()) {
+		seqinPath = pos.String("seqin", "input sequence file (may be omitted if standard input is provided)")
+	}
+
+	nocache := opt.Switch(0, "no-cache", "do not use or create cache")
+	seqoutPath := opt.String('o', "output", "-", "output sequence file (specifying `-` will force standard output)")
+	format := opt.String('F', "format", "", "output file format (defaults to same as input)")
+	split := opt.Switch('s', "split", "split existing feature locations at insertions instead of extending them")
+	reportPath := opt.String('r', "report", "", "file to report the variants found within CDS features (defaults to standard error)")
+
+	if err := ctx.Parse(pos, opt); err != nil {
+		return err
+	}
+
+	vcfFile, err := openDecompressed(*vcfPath)
+	if err != nil {
+		return ctx.Raise(fmt.Errorf("failed to open file %q: %v", *vcfPath, err))
+	}
+	defer vcfFile.Close()
+
+	h.Reset()
+	state := pars.NewState(attach(h, vcfFile))
+	result, err := pars.AsParser(seqio.VCFParser).Parse(state)
+	if err != nil {
+		return ctx.Raise(err)
+	}
+	table := result.Value.(seqio.VariantTable)
+	vcfSum := h.Sum(nil)
+
+	lookup := func(seq gts.Sequence) []gts.Variant {
+		if vv, ok := table.Lookup(seqio.SequenceID(seq)); ok {
+			return vv
+		}
+		if len(table) == 1 {
+			return table[0].Variants
+		}
+		return nil
+	}
+
+	reportFile := os.Stderr
+	if *reportPath != "" {
+		f, err := os.Create(*reportPath)
+		if err != nil {
+			return ctx.Raise(fmt.Errorf("failed to create file %q: %v", *reportPath, err))
+		}
+		defer f.Close()
+		reportFile = f
+	}
+	report := bufio.NewWriter(reportFile)
+	defer report.Flush()
+
+	d, err := newIODelegate(*seqinPath, *seqoutPath)
+	if err != nil {
+		return ctx.Raise(err)
+	}
+	defer d.Close()
+
+	filetype := seqio.Detect(*seqoutPath)
+	if *format != "" {
+		filetype = seqio.ToFileType(*format)
+	}
+
+	if !*nocache {
+		data := encodePayload([]tuple{
+			{"command", strings.Join(ctx.Name, "-")},
+			{"version", gts.Version.String()},
+			{"vcf", encodeToString(vcfSum)},
+			{"filetype", filetype},
+			{"split", *split},
+			{"report", *reportPath},
+		})
+
+		ok, err := d.TryCache(h, data)
+		if err != nil {
+			return ctx.Raise(err)
+		}
+
+		// Only the sequences are cached, so read the input again to report
+		// the variants.
+		if ok {
+			scanner := seqio.NewAutoScanner(d)
+			for scanner.Scan() {
+				seq := scanner.Value()
+				if err := reportVariants(report, seq, lookup(seq)); err != nil {
+					return ctx.Raise(err)
+				}
+			}
+			if err := scanner.Err(); err != nil {
+				return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
+			}
+			return ctx.Raise(report.Flush())
+		}
+	}
+
+	scanner := seqio.NewAutoScanner(d)
+	buffer := bufio.NewWriter(d)
+	writer := seqio.NewWriter(buffer, filetype)
+
+	for scanner.Scan() {
+		seq := scanner.Value()
+		id := seqio.SequenceID(seq)
+		variants := lookup(seq)
+
+		if err := reportVariants(report, seq, variants); err != nil {
+			return ctx.Raise(err)
+		}
+
+		seq, err := gts.ApplyVariants(seq, variants, !*split)
+		if err != nil {
+			return ctx.Raise(fmt.Errorf("in sequence %q: %v", id, err))
+		}
+
+		if _, err := writer.WriteSeq(seq); err != nil {
+			return ctx.Raise(err)
+		}
+
+		if err := buffer.Flush(); err != nil {
+			return ctx.Raise(err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
+	}
+
+	if err := closeWriter(writer, buffer); err != nil {
+		return ctx.Raise(err)
+	}
+
+	return ctx.Raise(report.Flush())
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/internal/testutils"
+	"github.com/go-gts/gts/seqio"
+)
+
+func TestReportVariants(t *testing.T) {
+	in := testutils.ReadTestfilePkg(t, "NC_001422.gb", "../../seqio")
+	scanner := seqio.NewAutoScanner(strings.NewReader(in))
+	if !scanner.Scan() {
+		t.Fatalf("scanner.Err() = %v", scanner.Err())
+	}
+	seq := scanner.Value()
+
+	variants := []gts.Variant{
+		{Pos: 99, Ref: []byte("a"), Alt: []byte("g"), ID: "rs1"},
+		{Pos: 2300, Ref: []byte("t"), Alt: []byte("tt"), ID: "."},
+	}
+
+	exp := "" +
+		"NC_001422.1\t100\ta\tg\trs1\tjoin(3981..5386,1..136)\tphiX174p01\n" +
+		"NC_001422.1\t100\ta\tg\trs1\tjoin(4497..5386,1..136)\tphiX174p02\n" +
+		"NC_001422.1\t100\ta\tg\trs1\t51..221\tphiX174p04\n"
+
+	b := strings.Builder{}
+	if err := reportVariants(&b, seq, variants); err != nil {
+		t.Fatalf("reportVariants(w, seq, variants): %v", err)
+	}
+	testutils.DiffLine(t, exp, b.String())
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-gts/flags"
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/cmd"
+	"github.com/go-gts/gts/seqio"
+)
+
+func init() {
+	flags.Register("pcr", "simulate a polymerase chain reaction with primer pairs", pcrFunc)
+}
+
+func pcrFunc(ctx *flags.Context) error {
+	h := newHash()
+	pos, opt := flags.Flags()
+
+	primersPath := pos.String("primers", "primer sequence file containing forward and reverse primers in pairs")
+
+	seqinPath := new(string)
+	*seqinPath = "-"
+	if cmd.IsTerminal(os.Stdin.Fd()) {
+		seqinPath = pos.String("seqin", "input sequence file (may be omitted if standard input is provided)")
+	}
+
+	nocache := opt.Switch(0, "no-cache", "do not use or create cache")
+	seqoutPath := opt.String('o', "output", "-", "output sequence file (specifying `-` will force standard output)")
+	format := opt.String('F', "format", "", "output file format (defaults to same as input)")
+	featureKey := opt.String('k', "key", "primer_bind", "key for the primer binding site features")
+	mismatches := opt.Int('m', "mismatches", 0, "maximum number of mismatches allowed in a primer")
+	anchor := opt.Int('a', "anchor", 3, "number of bases at the 3' end of a primer which must match exactly")
+	maxSize := opt.Int(0, "max-size", 0, "maximum product size (zero for no limit)")
+	report := opt.Switch('r', "report", "report the primer binding sites and product sizes instead of the products")
+
+	if err := ctx.Parse(pos, opt); err != nil {
+		return err
+	}
+
+	if *mismatches < 0 {
+		return ctx.Raise(fmt.Errorf("number of mismatches must be non-negative: %d", *mismatches))
+	}
+	if *anchor < 0 {
+		return ctx.Raise(fmt.Errorf("number of anchored bases must be non-negative: %d", *anchor))
+	}
+	if *maxSize < 0 {
+		return ctx.Raise(fmt.Errorf("maximum product size must be non-negative: %d", *maxSize))
+	}
+
+	primersFile, err := openDecompressed(*primersPath)
+	if err != nil {
+		return ctx.Raise(err)
+	}
+	defer primersFile.Close()
+
+	h.Reset()
+	primers := []gts.Sequence{}
+	primerScanner := seqio.NewAutoScanner(attach(h, primersFile))
+	for primerScanner.Scan() {
+		primers = append(primers, primerScanner.Value())
+	}
+	if err := primerScanner.Err(); err != nil {
+		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
+	}
+	if len(primers) == 0 || len(primers)%2 != 0 {
+		return ctx.Raise(fmt.Errorf("primer sequence file %q must contain primers in pairs", *primersPath))
+	}
+	primerSum := h.Sum(nil)
+
+	names := make([]string, len(primers))
+	for i, primer := range primers {
+		names[i] = seqio.SequenceID(primer)
+		if names[i] == "" {
+			names[i] = fmt.Sprintf("primer_%d", i+1)
+		}
+	}
+
+	d, err := newIODelegate(*seqinPath, *seqoutPath)
+	if err != nil {
+		return ctx.Raise(err)
+	}
+	defer d.Close()
+
+	filetype := seqio.Detect(*seqoutPath)
+	if *format != "" {
+		filetype = seqio.ToFileType(*format)
+	}
+
+	if !*nocache {
+		data := encodePayload([]tuple{
+			{"command", strings.Join(ctx.Name, "-")},
+			{"version", gts.Version.String()},
+			{"primers", encodeToString(primerSum)},
+			{"filetype", filetype},
+			{"featureKey", *featureKey},
+			{"mismatches", *mismatches},
+			{"anchor", *anchor},
+			{"maxSize", *maxSize},
+			{"report", *report},
+		})
+
+		ok, err := d.TryCache(h, data)
+		if ok || err != nil {
+			return ctx.Raise(err)
+		}
+	}
+
+	scanner := seqio.NewAutoScanner(d)
+	buffer := bufio.NewWriter(d)
+	writer := seqio.NewWriter(buffer, filetype)
+
+	for scanner.Scan() {
+		seq := scanner.Value()
+		id := seqio.SequenceID(seq)
+
+		for i := 0; i < len(primers); i += 2 {
+			fwd, rev := primers[i], primers[i+1]
+			fname, rname := names[i], names[i+1]
+			amplicons := gts.PCR(seq, fwd, rev, *mismatches, *anchor, *maxSize)
+			for _, amplicon := range amplicons {
+				product := amplicon.Product
+				n := gts.Len(product)
+
+				if *report {
+					line := fmt.Sprintf(
+						"%s\t%s\t%s\t%s\t%s\t%d\n",
+						id, fname, rname,
+						amplicon.Forward, amplicon.Reverse, n,
+					)
+					if _, err := io.WriteString(buffer, line); err != nil {
+						return ctx.Raise(err)
+					}
+					continue
+				}
+
+				fprops, rprops := gts.Props{}, gts.Props{}
+				fprops.Add("note", fname)
+				rprops.Add("note", rname)
+				floc := gts.Range(0, gts.Len(fwd))
+				rloc := gts.Range(n-gts.Len(rev), n).Complement()
+
+				ff := product.Features()
+				ff = ff.Insert(gts.NewFeature(*featureKey, floc, fprops))
+				ff = ff.Insert(gts.NewFeature(*featureKey, rloc, rprops))
+				product = gts.WithFeatures(product, ff)
+
+				if _, err := writer.WriteSeq(product); err != nil {
+					return ctx.Raise(err)
+				}
+			}
+		}
+
+		if err := buffer.Flush(); err != nil {
+			return ctx.Raise(err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
+	}
+
+	return ctx.Raise(closeWriter(writer, buffer))
+}
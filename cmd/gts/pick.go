@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -17,12 +18,12 @@ func init() {
 	flags.Register("pick", "pick sequence(s) from multiple sequences", pickFunc)
 }
 
-type picker func(i int) bool
+type picker func(i int, seq gts.Sequence) bool
 
 func pickAll(pickers ...picker) picker {
-	return func(i int) bool {
+	return func(i int, seq gts.Sequence) bool {
 		for _, pick := range pickers {
-			if !pick(i) {
+			if !pick(i, seq) {
 				return false
 			}
 		}
@@ -31,9 +32,9 @@ func pickAll(pickers ...picker) picker {
 }
 
 func pickAny(pickers ...picker) picker {
-	return func(i int) bool {
+	return func(i int, seq gts.Sequence) bool {
 		for _, pick := range pickers {
-			if pick(i) {
+			if pick(i, seq) {
 				return true
 			}
 		}
@@ -42,13 +43,13 @@ func pickAny(pickers ...picker) picker {
 }
 
 func pickAfter(m int) picker {
-	return func(i int) bool {
+	return func(i int, seq gts.Sequence) bool {
 		return m <= i
 	}
 }
 
 func pickBefore(n int) picker {
-	return func(i int) bool {
+	return func(i int, seq gts.Sequence) bool {
 		return i <= n
 	}
 }
@@ -58,11 +59,77 @@ func pickBetween(m, n int) picker {
 }
 
 func pickOne(n int) picker {
-	return func(i int) bool {
+	return func(i int, seq gts.Sequence) bool {
 		return n == i
 	}
 }
 
+// sequenceIDs returns the identifiers a sequence can be picked by: the ID of
+// the sequence, and the accession and versioned accession of a record.
+func sequenceIDs(seq gts.Sequence) []string {
+	ids := []string{seqio.SequenceID(seq)}
+	if info, ok := seq.Info().(seqio.GenBankFields); ok {
+		ids = append(ids, info.LocusName, info.Accession, info.Version)
+	}
+	return ids
+}
+
+func pickID(ids ...string) picker {
+	set := make(map[string]struct{})
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return func(i int, seq gts.Sequence) bool {
+		if seq == nil {
+			return false
+		}
+		for _, id := range sequenceIDs(seq) {
+			if _, ok := set[id]; ok && id != "" {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func pickDefinition(re *regexp.Regexp) picker {
+	return func(i int, seq gts.Sequence) bool {
+		return re.MatchString(sequenceDefinition(seq))
+	}
+}
+
+func pickOrganism(re *regexp.Regexp) picker {
+	return func(i int, seq gts.Sequence) bool {
+		if info, ok := seq.Info().(seqio.GenBankFields); ok {
+			return re.MatchString(info.Source.Species) || re.MatchString(info.Source.Name)
+		}
+		return false
+	}
+}
+
+func pickLength(pick picker) picker {
+	return func(i int, seq gts.Sequence) bool {
+		return pick(gts.Len(seq), seq)
+	}
+}
+
+func pickTopology(t gts.Topology) picker {
+	return func(i int, seq gts.Sequence) bool {
+		return gts.TopologyOf(seq) == t
+	}
+}
+
+// pickMolecule picks sequences of the given molecule type, where DNA will
+// also match single and double stranded DNA.
+func pickMolecule(mol gts.Molecule) picker {
+	return func(i int, seq gts.Sequence) bool {
+		if info, ok := seq.Info().(seqio.GenBankFields); ok {
+			return info.Molecule == mol || (mol == gts.DNA && strings.HasSuffix(string(info.Molecule), "-DNA"))
+		}
+		return false
+	}
+}
+
 func mustAtoi(s string) int {
 	n, err := strconv.Atoi(s)
 	if err != nil {
@@ -71,35 +138,61 @@ func mustAtoi(s string) int {
 	return n
 }
 
-func asPicker(list string) picker {
+func isOrdinal(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+// asPicker converts a list to a picker. A list is a comma separated set of
+// numbers, number ranges, and sequence IDs. The second return value reports
+// whether the list contains any sequence IDs.
+func asPicker(list string) (picker, bool) {
 	blocks := strings.Split(list, ",")
 	pickers := make([]picker, len(blocks))
+	ids := false
 	for i, block := range blocks {
 		index := strings.IndexByte(block, '-')
-		switch index {
-		case -1:
-			n := mustAtoi(block)
-			pickers[i] = pickOne(n)
-		case 0:
-			n := mustAtoi(block[index+1:])
-			pickers[i] = pickBefore(n)
-		case len(block) - 1:
-			m := mustAtoi(block[:index])
-			pickers[i] = pickAfter(m)
+		switch {
+		case index < 0 && isOrdinal(block):
+			pickers[i] = pickOne(mustAtoi(block))
+		case index < 0 || !isOrdinal(strings.Replace(block, "-", "", 1)):
+			pickers[i] = pickID(block)
+			ids = true
+		case index == 0:
+			pickers[i] = pickBefore(mustAtoi(block[index+1:]))
+		case index == len(block)-1:
+			pickers[i] = pickAfter(mustAtoi(block[:index]))
 		default:
 			n := mustAtoi(block[index+1:])
 			m := mustAtoi(block[:index])
 			pickers[i] = pickBetween(m, n)
 		}
 	}
-	return pickAny(pickers...)
+	return pickAny(pickers...), ids
+}
+
+func readIDFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ids := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+			ids = append(ids, fields[0])
+		}
+	}
+	return ids, scanner.Err()
 }
 
 func pickFunc(ctx *flags.Context) error {
 	h := newHash()
 	pos, opt := flags.Flags()
 
-	list := pos.String("list", "list of sequences to pick (identical to the list option in cut)")
+	list := pos.String("list", "list of sequences to pick (identical to the list option in cut, but may also contain sequence IDs)")
 
 	seqinPath := new(string)
 	*seqinPath = "-"
@@ -111,12 +204,74 @@ func pickFunc(ctx *flags.Context) error {
 	seqoutPath := opt.String('o', "output", "-", "output sequence file (specifying `-` will force standard output)")
 	format := opt.String('F', "format", "", "output file format (defaults to same as input)")
 	feature := opt.Switch('f', "feature", "pick features instead of sequences")
+	indexFlag := opt.String('i', "index", "", "index file to use (defaults to the input filename suffixed with `.gtsi` if it exists)")
+	idFile := opt.String(0, "id-file", "", "file containing the IDs of sequences to pick, one per line")
+	definition := opt.String('d', "definition", "", "regular expression to match against the definition or description of the sequence")
+	organism := opt.String(0, "organism", "", "regular expression to match against the source organism of the sequence")
+	length := opt.String('l', "length", "", "list of sequence lengths to pick (same syntax as the list argument)")
+	topology := opt.String('t', "topology", "", "topology of the sequences to pick (linear or circular)")
+	molecule := opt.String('m', "molecule", "", "molecule type of the sequences to pick (DNA, RNA, AA, ss-DNA, or ds-DNA)")
 
 	if err := ctx.Parse(pos, opt); err != nil {
 		return err
 	}
 
-	pick := asPicker(*list)
+	pick, hasIDs := asPicker(*list)
+
+	// The filters restrict the sequences by their metadata in addition to the
+	// list. In feature mode, the filters select the sequences to output.
+	filters := []picker{}
+
+	ids := []string{}
+	if *idFile != "" {
+		var err error
+		if ids, err = readIDFile(*idFile); err != nil {
+			return ctx.Raise(fmt.Errorf("failed to read ID file %q: %v", *idFile, err))
+		}
+		filters = append(filters, pickID(ids...))
+	}
+
+	if *definition != "" {
+		re, err := regexp.Compile(*definition)
+		if err != nil {
+			return ctx.Raise(fmt.Errorf("invalid definition pattern %q: %v", *definition, err))
+		}
+		filters = append(filters, pickDefinition(re))
+	}
+
+	if *organism != "" {
+		re, err := regexp.Compile(*organism)
+		if err != nil {
+			return ctx.Raise(fmt.Errorf("invalid organism pattern %q: %v", *organism, err))
+		}
+		filters = append(filters, pickOrganism(re))
+	}
+
+	if *length != "" {
+		p, ids := asPicker(*length)
+		if ids {
+			return ctx.Raise(fmt.Errorf("invalid length list %q", *length))
+		}
+		filters = append(filters, pickLength(p))
+	}
+
+	if *topology != "" {
+		t, err := gts.AsTopology(*topology)
+		if err != nil {
+			return ctx.Raise(err)
+		}
+		filters = append(filters, pickTopology(t))
+	}
+
+	if *molecule != "" {
+		mol, err := gts.AsMolecule(*molecule)
+		if err != nil {
+			return ctx.Raise(err)
+		}
+		filters = append(filters, pickMolecule(mol))
+	}
+
+	filter := pickAll(filters...)
 
 	d, err := newIODelegate(*seqinPath, *seqoutPath)
 	if err != nil {
@@ -129,11 +284,57 @@ func pickFunc(ctx *flags.Context) error {
 		filetype = seqio.ToFileType(*format)
 	}
 
+	if *indexFlag == "" && *seqinPath != "-" {
+		*indexFlag = indexPath(*seqinPath)
+	}
+
+	// Jump straight to the picked sequences if the input file is indexed.
+	if !*feature {
+		if index := loadIndex(*indexFlag, d.infile); index != nil {
+			buffer := bufio.NewWriter(d)
+			writer := seqio.NewWriter(buffer, filetype)
+
+			// Ordinal lists can be checked without reading the sequence.
+			ordinal := !hasIDs && len(filters) == 0
+
+			for i, entry := range index.Entries {
+				if ordinal && !pick(i+1, nil) {
+					continue
+				}
+
+				seq, err := seqio.ReadRecord(d.infile, entry)
+				if err != nil {
+					return ctx.Raise(err)
+				}
+
+				if !pick(i+1, seq) || !filter(i+1, seq) {
+					continue
+				}
+
+				if _, err := writer.WriteSeq(seq); err != nil {
+					return ctx.Raise(err)
+				}
+
+				if err := buffer.Flush(); err != nil {
+					return ctx.Raise(err)
+				}
+			}
+
+			return ctx.Raise(closeWriter(writer, buffer))
+		}
+	}
+
 	if !*nocache {
 		data := encodePayload([]tuple{
 			{"command", strings.Join(ctx.Name, "-")},
 			{"version", gts.Version.String()},
 			{"list", *list},
+			{"ids", ids},
+			{"definition", *definition},
+			{"organism", *organism},
+			{"length", *length},
+			{"topology", *topology},
+			{"molecule", *molecule},
 			{"feature", *feature},
 			{"filetype", filetype},
 		})
@@ -153,12 +354,16 @@ func pickFunc(ctx *flags.Context) error {
 		seq := scanner.Value()
 		i++
 
-		if pick(i) || *feature {
+		if !filter(i, seq) {
+			continue
+		}
+
+		if pick(i, seq) || *feature {
 			if *feature {
 				ff := seq.Features()
 				indices := make([]int, 0, len(ff))
 				for j := range ff {
-					if pick(j) {
+					if pick(j, nil) {
 						indices = append(indices, j)
 					}
 				}
@@ -183,5 +388,5 @@ func pickFunc(ctx *flags.Context) error {
 		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
 	}
 
-	return nil
+	return ctx.Raise(closeWriter(writer, buffer))
 }
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"runtime"
+
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/seqio"
+)
+
+// transform converts a single input sequence into the sequences to output.
+type transform func(seq gts.Sequence) ([]gts.Sequence, error)
+
+type pipelineResult struct {
+	seqs []gts.Sequence
+	err  error
+}
+
+type pipelineTask struct {
+	seq  gts.Sequence
+	done chan pipelineResult
+}
+
+// numJobs returns the number of workers to use for the given --jobs value,
+// where a non-positive value denotes the number of available CPUs.
+func numJobs(jobs int) int {
+	if jobs <= 0 {
+		return runtime.NumCPU()
+	}
+	return jobs
+}
+
+// runPipeline reads the sequences from the scanner, applies the transform to
+// each of them and writes the results. With more than one job, the sequences
+// are transformed by a pool of workers while the results are still written in
+// the order of the input sequences.
+func runPipeline(scanner *seqio.Scanner, buffer *bufio.Writer, writer seqio.SeqWriter, jobs int, f transform) error {
+	write := func(seqs []gts.Sequence) error {
+		for _, seq := range seqs {
+			if _, err := writer.WriteSeq(seq); err != nil {
+				return err
+			}
+		}
+		return buffer.Flush()
+	}
+
+	if jobs = numJobs(jobs); jobs == 1 {
+		for scanner.Scan() {
+			seqs, err := f(scanner.Value())
+			if err != nil {
+				return err
+			}
+			if err := write(seqs); err != nil {
+				return err
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("encountered error in scanner: %v", err)
+		}
+		return closeWriter(writer, buffer)
+	}
+
+	tasks := make(chan pipelineTask)
+	order := make(chan chan pipelineResult, 2*jobs)
+	quit := make(chan struct{})
+	defer close(quit)
+
+	for i := 0; i < jobs; i++ {
+		go func() {
+			for task := range tasks {
+				seqs, err := f(task.seq)
+				task.done <- pipelineResult{seqs, err}
+			}
+		}()
+	}
+
+	var scanErr error
+	go func() {
+		defer close(order)
+		defer close(tasks)
+		for scanner.Scan() {
+			done := make(chan pipelineResult, 1)
+			select {
+			case order <- done:
+			case <-quit:
+				return
+			}
+			tasks <- pipelineTask{scanner.Value(), done}
+		}
+		scanErr = scanner.Err()
+	}()
+
+	for done := range order {
+		res := <-done
+		if res.err != nil {
+			return res.err
+		}
+		if err := write(res.seqs); err != nil {
+			return err
+		}
+	}
+
+	if scanErr != nil {
+		return fmt.Errorf("encountered error in scanner: %v", scanErr)
+	}
+
+	return closeWriter(writer, buffer)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/internal/testutils"
+	"github.com/go-gts/gts/seqio"
+)
+
+func TestRunPipelineGenBank(t *testing.T) {
+	in := testutils.ReadTestfilePkg(t, "NC_001422.gb", "../../seqio")
+	in += testutils.ReadTestfilePkg(t, "NC_001422_part.gb", "../../seqio")
+
+	for _, jobs := range []int{1, 4} {
+		b := strings.Builder{}
+		buffer := bufio.NewWriter(&b)
+		writer := seqio.NewWriter(buffer, seqio.GenBankFile)
+
+		// GenBank input is read using the streaming reader.
+		scanner := seqio.NewAutoScanner(strings.NewReader(in))
+		err := runPipeline(scanner, buffer, writer, jobs, func(seq gts.Sequence) ([]gts.Sequence, error) {
+			if _, ok := seq.(seqio.GenBank); !ok {
+				t.Errorf("seq.(type) = %T, want %T", seq, seqio.GenBank{})
+			}
+			return []gts.Sequence{seq}, nil
+		})
+		if err != nil {
+			t.Errorf("runPipeline(jobs = %d): %v", jobs, err)
+			continue
+		}
+		testutils.DiffLine(t, in, b.String())
+	}
+}
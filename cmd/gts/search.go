@@ -2,8 +2,10 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/go-gts/flags"
@@ -35,11 +37,22 @@ func searchFunc(ctx *flags.Context) error {
 	propstrs := opt.StringSlice('q', "qualifier", nil, "qualifier key-value pairs (syntax: key=value))")
 	exact := opt.Switch('e', "exact", "match the exact pattern even for ambiguous letters")
 	nocomplement := opt.Switch(0, "no-complement", "do not match the complement strand")
+	mismatches := opt.Int('m', "mismatches", 0, "maximum number of mismatches allowed in a match")
+	indels := opt.Switch(0, "indels", "count insertions and deletions as mismatches")
+	jobs := opt.Int('j', "jobs", 1, "number of sequences to process in parallel (0 to use all available CPUs)")
 
 	if err := ctx.Parse(pos, opt); err != nil {
 		return err
 	}
 
+	if *mismatches < 0 {
+		return ctx.Raise(fmt.Errorf("number of mismatches must be non-negative: %d", *mismatches))
+	}
+
+	if *indels && *mismatches == 0 {
+		return ctx.Raise(errors.New("--indels requires a positive number of --mismatches"))
+	}
+
 	queries := []gts.Sequence{}
 	queryBytes := []byte(*queryPath)
 
@@ -51,10 +64,11 @@ func searchFunc(ctx *flags.Context) error {
 		queries = append(queries, query)
 
 	default:
-		queryFile, err := os.Open(*queryPath)
+		queryFile, err := openDecompressed(*queryPath)
 		if err != nil {
 			return ctx.Raise(err)
 		}
+		defer queryFile.Close()
 
 		r := attach(h, queryFile)
 		scanner := seqio.NewAutoScanner(r)
@@ -99,6 +113,8 @@ func searchFunc(ctx *flags.Context) error {
 			{"propstrs", *propstrs},
 			{"exact", *exact},
 			{"nocomplement", *nocomplement},
+			{"mismatches", *mismatches},
+			{"indels", *indels},
 		})
 
 		ok, err := d.TryCache(h, data)
@@ -107,50 +123,67 @@ func searchFunc(ctx *flags.Context) error {
 		}
 	}
 
-	match := gts.Match
-	if *exact {
-		match = gts.Search
+	match := func(seq, query gts.Sequence) []gts.ApproxMatch {
+		if *mismatches > 0 {
+			if *exact {
+				return gts.SearchApprox(seq, query, *mismatches, *indels)
+			}
+			return gts.MatchApprox(seq, query, *mismatches, *indels)
+		}
+
+		search := gts.Match
+		if *exact {
+			search = gts.Search
+		}
+
+		segments := search(seq, query)
+		matches := make([]gts.ApproxMatch, len(segments))
+		for i, segment := range segments {
+			matches[i] = gts.ApproxMatch{Segment: segment, Distance: 0}
+		}
+		return matches
 	}
 
 	scanner := seqio.NewAutoScanner(d)
 	buffer := bufio.NewWriter(d)
 	writer := seqio.NewWriter(buffer, filetype)
 
-	for scanner.Scan() {
-		seq := scanner.Value()
+	err = runPipeline(scanner, buffer, writer, *jobs, func(seq gts.Sequence) ([]gts.Sequence, error) {
+		n := gts.Len(seq)
+
 		cmp := gts.Reverse(gts.Complement(gts.New(nil, nil, seq.Bytes())))
+		cmp = gts.WithBytes(seq, cmp.Bytes())
+
 		ff := seq.Features()
+		annotate := func(m gts.ApproxMatch, complement bool) {
+			head, tail := gts.Unpack(m.Segment)
+			if tail <= head {
+				return
+			}
+			loc := gts.Range(head, tail).Normalize(n)
+			if complement {
+				loc = loc.Reverse(n).Complement()
+			}
+			fprops := props
+			if *mismatches > 0 {
+				fprops = props.Clone()
+				fprops.Add("mismatches", strconv.Itoa(m.Distance))
+			}
+			ff = ff.Insert(gts.NewFeature(*featureKey, loc, fprops))
+		}
+
 		for _, query := range queries {
-			fwd := match(seq, query)
-			for _, segment := range fwd {
-				head, tail := gts.Unpack(segment)
-				f := gts.NewFeature(*featureKey, gts.Range(head, tail), props)
-				ff = ff.Insert(f)
+			for _, m := range match(seq, query) {
+				annotate(m, false)
 			}
 			if !*nocomplement {
-				bwd := match(cmp, query)
-				for _, segment := range bwd {
-					head, tail := gts.Unpack(segment)
-					loc := gts.Range(head, tail)
-					loc = loc.Reverse(gts.Len(seq)).(gts.Ranged)
-					f := gts.NewFeature(*featureKey, loc.Complement(), props)
-					ff = ff.Insert(f)
+				for _, m := range match(cmp, query) {
+					annotate(m, true)
 				}
 			}
 		}
-		seq = gts.WithFeatures(seq, ff)
-		if _, err := writer.WriteSeq(seq); err != nil {
-			return ctx.Raise(err)
-		}
-
-		if err := buffer.Flush(); err != nil {
-			return ctx.Raise(err)
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
-	}
+		return []gts.Sequence{gts.WithFeatures(seq, ff)}, nil
+	})
 
-	return nil
+	return ctx.Raise(err)
 }
@@ -34,6 +34,7 @@ func selectFunc(ctx *flags.Context) error {
 	format := opt.String('F', "format", "", "output file format (defaults to same as input)")
 	strand := opt.String('s', "strand", "both", "strand to select features from (`both`, `forward`, or `reverse`)")
 	invert := opt.Switch('v', "invert-match", "select features that do not match the given criteria")
+	jobs := opt.Int('j', "jobs", 1, "number of sequences to process in parallel (0 to use all available CPUs)")
 
 	if err := ctx.Parse(pos, opt); err != nil {
 		return err
@@ -93,22 +94,10 @@ func selectFunc(ctx *flags.Context) error {
 	buffer := bufio.NewWriter(d)
 	writer := seqio.NewWriter(buffer, filetype)
 
-	for scanner.Scan() {
-		seq := scanner.Value()
+	err = runPipeline(scanner, buffer, writer, *jobs, func(seq gts.Sequence) ([]gts.Sequence, error) {
 		ff := seq.Features().Filter(filter)
-		seq = gts.WithFeatures(seq, ff)
-		if _, err := writer.WriteSeq(seq); err != nil {
-			return ctx.Raise(err)
-		}
-
-		if err := buffer.Flush(); err != nil {
-			return ctx.Raise(err)
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
-	}
+		return []gts.Sequence{gts.WithFeatures(seq, ff)}, nil
+	})
 
-	return nil
+	return ctx.Raise(err)
 }
@@ -2,7 +2,10 @@ package main
 
 import (
 	"bufio"
+	"container/heap"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"sort"
 	"strings"
@@ -17,18 +20,309 @@ func init() {
 	flags.Register("sort", "sort the list of sequences", sortFunc)
 }
 
-type byLength []gts.Sequence
+// sortKey extracts a value to compare the sequences by. The extracted values
+// must either all be float64 or all be string values. Keys with desc set will
+// order the sequences from the largest to the smallest value.
+type sortKey struct {
+	extract func(seq gts.Sequence) interface{}
+	desc    bool
+}
+
+func sequenceDefinition(seq gts.Sequence) string {
+	switch info := seq.Info().(type) {
+	case seqio.GenBankFields:
+		return info.Definition
+	case string:
+		return info
+	case fmt.Stringer:
+		return info.String()
+	default:
+		return ""
+	}
+}
+
+func sequenceOrganism(seq gts.Sequence) string {
+	if info, ok := seq.Info().(seqio.GenBankFields); ok {
+		return info.Source.Name
+	}
+	return ""
+}
+
+func sequenceDate(seq gts.Sequence) float64 {
+	if info, ok := seq.Info().(seqio.GenBankFields); ok {
+		date := info.Date
+		return float64(date.Year*10000 + int(date.Month)*100 + date.Day)
+	}
+	return 0
+}
+
+func sequenceGC(seq gts.Sequence) float64 {
+	p := seq.Bytes()
+	if len(p) == 0 {
+		return 0
+	}
+	n := 0
+	for _, c := range p {
+		switch c {
+		case 'g', 'c', 'G', 'C', 's', 'S':
+			n++
+		}
+	}
+	return float64(n) / float64(len(p))
+}
+
+func sourceQualifier(name string) func(seq gts.Sequence) interface{} {
+	return func(seq gts.Sequence) interface{} {
+		for _, f := range seq.Features() {
+			if f.Key == "source" {
+				if values := f.Props.Get(name); len(values) > 0 {
+					return values[0]
+				}
+			}
+		}
+		return ""
+	}
+}
+
+// asSortKey converts a key name to a sortKey. Any name beginning with a slash
+// denotes a qualifier of the source feature.
+func asSortKey(name string) (sortKey, error) {
+	switch name {
+	case "length":
+		return sortKey{func(seq gts.Sequence) interface{} {
+			return float64(gts.Len(seq))
+		}, true}, nil
+	case "id":
+		return sortKey{func(seq gts.Sequence) interface{} {
+			return seqio.SequenceID(seq)
+		}, false}, nil
+	case "definition":
+		return sortKey{func(seq gts.Sequence) interface{} {
+			return sequenceDefinition(seq)
+		}, false}, nil
+	case "organism":
+		return sortKey{func(seq gts.Sequence) interface{} {
+			return sequenceOrganism(seq)
+		}, false}, nil
+	case "gc":
+		return sortKey{func(seq gts.Sequence) interface{} {
+			return sequenceGC(seq)
+		}, true}, nil
+	case "features":
+		return sortKey{func(seq gts.Sequence) interface{} {
+			return float64(len(seq.Features()))
+		}, true}, nil
+	case "date":
+		return sortKey{func(seq gts.Sequence) interface{} {
+			return sequenceDate(seq)
+		}, false}, nil
+	}
+
+	if strings.HasPrefix(name, "/") && len(name) > 1 {
+		return sortKey{sourceQualifier(name[1:]), false}, nil
+	}
+
+	return sortKey{}, fmt.Errorf("unknown sort key: %q", name)
+}
+
+func compareSortValues(a, b interface{}) int {
+	switch a := a.(type) {
+	case float64:
+		b := b.(float64)
+		switch {
+		case a < b:
+			return -1
+		case b < a:
+			return 1
+		}
+	case string:
+		return strings.Compare(a, b.(string))
+	}
+	return 0
+}
+
+// sortItem holds a sequence along with its values for each sort key so that
+// the values are only computed once per sequence.
+type sortItem struct {
+	seq    gts.Sequence
+	values []interface{}
+}
+
+func newSortItem(seq gts.Sequence, keys []sortKey) sortItem {
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		values[i] = key.extract(seq)
+	}
+	return sortItem{seq, values}
+}
+
+func sortLess(a, b sortItem, keys []sortKey, reverse bool) bool {
+	for i, key := range keys {
+		c := compareSortValues(a.values[i], b.values[i])
+		if key.desc != reverse {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+	}
+	return false
+}
+
+// sortItems sorts the items by the keys, retaining the order of the items
+// with equal values for all keys.
+func sortItems(items []sortItem, keys []sortKey, reverse bool) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return sortLess(items[i], items[j], keys, reverse)
+	})
+}
+
+// sortRun is a sorted run of sequences written to a temporary file.
+type sortRun struct {
+	index   int
+	file    *os.File
+	scanner *seqio.Scanner
+	head    sortItem
+}
+
+type sortRunHeap struct {
+	runs    []*sortRun
+	keys    []sortKey
+	reverse bool
+}
+
+func (h sortRunHeap) Len() int {
+	return len(h.runs)
+}
+
+func (h sortRunHeap) Less(i, j int) bool {
+	a, b := h.runs[i], h.runs[j]
+	switch {
+	case sortLess(a.head, b.head, h.keys, h.reverse):
+		return true
+	case sortLess(b.head, a.head, h.keys, h.reverse):
+		return false
+	default:
+		return a.index < b.index
+	}
+}
 
-func (ss byLength) Len() int {
-	return len(ss)
+func (h sortRunHeap) Swap(i, j int) {
+	h.runs[i], h.runs[j] = h.runs[j], h.runs[i]
 }
 
-func (ss byLength) Less(i, j int) bool {
-	return gts.Len(ss[j]) < gts.Len(ss[i])
+func (h *sortRunHeap) Push(x interface{}) {
+	h.runs = append(h.runs, x.(*sortRun))
 }
 
-func (ss byLength) Swap(i, j int) {
-	ss[i], ss[j] = ss[j], ss[i]
+func (h *sortRunHeap) Pop() interface{} {
+	n := len(h.runs)
+	run := h.runs[n-1]
+	h.runs = h.runs[:n-1]
+	return run
+}
+
+// writeSortRun writes the sorted items to a temporary file.
+func writeSortRun(items []sortItem) (*os.File, error) {
+	f, err := ioutil.TempFile("", "gts-sort-*")
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := bufio.NewWriter(f)
+	writer := seqio.NewWriter(buffer, seqio.DefaultFile)
+	for _, item := range items {
+		if _, err := writer.WriteSeq(item.seq); err != nil {
+			return f, err
+		}
+	}
+	if err := buffer.Flush(); err != nil {
+		return f, err
+	}
+
+	_, err = f.Seek(0, io.SeekStart)
+	return f, err
+}
+
+// externalSort sorts the sequences from the scanner while holding at most n
+// sequences in memory at once. The sequences are sorted in runs of n that are
+// written to temporary files, which are then merged to call write with each
+// sequence in the sorted order.
+func externalSort(scanner *seqio.Scanner, n int, keys []sortKey, reverse bool, write func(seq gts.Sequence) error) error {
+	runs := []*sortRun{}
+	defer func() {
+		for _, run := range runs {
+			run.file.Close()
+			os.Remove(run.file.Name())
+		}
+	}()
+
+	items := make([]sortItem, 0, n)
+	flush := func() error {
+		if len(items) == 0 {
+			return nil
+		}
+		sortItems(items, keys, reverse)
+		f, err := writeSortRun(items)
+		if f != nil {
+			runs = append(runs, &sortRun{index: len(runs), file: f})
+		}
+		items = items[:0]
+		return err
+	}
+
+	for scanner.Scan() {
+		items = append(items, newSortItem(scanner.Value(), keys))
+		if len(items) == n {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("encountered error in scanner: %v", err)
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	h := &sortRunHeap{nil, keys, reverse}
+	advance := func(run *sortRun) (bool, error) {
+		if !run.scanner.Scan() {
+			return false, run.scanner.Err()
+		}
+		run.head = newSortItem(run.scanner.Value(), keys)
+		return true, nil
+	}
+
+	for _, run := range runs {
+		run.scanner = seqio.NewAutoScanner(bufio.NewReader(run.file))
+		ok, err := advance(run)
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Push(h, run)
+		}
+	}
+
+	for h.Len() > 0 {
+		run := h.runs[0]
+		if err := write(run.head.seq); err != nil {
+			return err
+		}
+		ok, err := advance(run)
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+
+	return nil
 }
 
 func sortFunc(ctx *flags.Context) error {
@@ -45,11 +339,28 @@ func sortFunc(ctx *flags.Context) error {
 	seqoutPath := opt.String('o', "output", "-", "output sequence file (specifying `-` will force standard output)")
 	format := opt.String('F', "format", "", "output file format (defaults to same as input)")
 	reverse := opt.Switch('r', "reverse", "reverse the sort order")
+	keyNames := opt.StringSlice('k', "key", nil, "key(s) to sort the sequences by, separated by commas (defaults to length)")
+	bufferSize := opt.Int('b', "buffer", 0, "maximum number of sequences to hold in memory (0 to hold all sequences)")
 
 	if err := ctx.Parse(pos, opt); err != nil {
 		return err
 	}
 
+	if len(*keyNames) == 0 {
+		*keyNames = []string{"length"}
+	}
+
+	keys := []sortKey{}
+	for _, names := range *keyNames {
+		for _, name := range strings.Split(names, ",") {
+			key, err := asSortKey(name)
+			if err != nil {
+				return ctx.Raise(err)
+			}
+			keys = append(keys, key)
+		}
+	}
+
 	d, err := newIODelegate(*seqinPath, *seqoutPath)
 	if err != nil {
 		return ctx.Raise(err)
@@ -66,6 +377,7 @@ func sortFunc(ctx *flags.Context) error {
 			{"command", strings.Join(ctx.Name, "-")},
 			{"version", gts.Version.String()},
 			{"reverse", *reverse},
+			{"keys", *keyNames},
 			{"filetype", filetype},
 		})
 
@@ -74,36 +386,41 @@ func sortFunc(ctx *flags.Context) error {
 			return ctx.Raise(err)
 		}
 	}
-	seqs := []gts.Sequence{}
-	scanner := seqio.NewAutoScanner(d)
-	for scanner.Scan() {
-		seq := scanner.Value()
-		seqs = append(seqs, seq)
-	}
-
-	var iface sort.Interface
-	iface = byLength(seqs)
-	if *reverse {
-		iface = sort.Reverse(iface)
-	}
-	sort.Sort(iface)
 
+	scanner := seqio.NewAutoScanner(d)
 	buffer := bufio.NewWriter(d)
 	writer := seqio.NewWriter(buffer, filetype)
 
-	for _, seq := range seqs {
+	write := func(seq gts.Sequence) error {
 		if _, err := writer.WriteSeq(seq); err != nil {
-			return ctx.Raise(err)
+			return err
 		}
+		return buffer.Flush()
+	}
 
-		if err := buffer.Flush(); err != nil {
+	if *bufferSize > 0 {
+		if err := externalSort(scanner, *bufferSize, keys, *reverse, write); err != nil {
 			return ctx.Raise(err)
 		}
+		return ctx.Raise(closeWriter(writer, buffer))
+	}
+
+	items := []sortItem{}
+	for scanner.Scan() {
+		items = append(items, newSortItem(scanner.Value(), keys))
 	}
 
 	if err := scanner.Err(); err != nil {
 		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
 	}
 
-	return nil
+	sortItems(items, keys, *reverse)
+
+	for _, item := range items {
+		if err := write(item.seq); err != nil {
+			return ctx.Raise(err)
+		}
+	}
+
+	return ctx.Raise(closeWriter(writer, buffer))
 }
@@ -79,6 +79,8 @@ func splitFunc(ctx *flags.Context) error {
 		switch v := seq.(type) {
 		case seqio.GenBank:
 			top = v.Fields.Topology
+		case seqio.EMBL:
+			top = v.Fields.Topology
 		}
 
 		switch {
@@ -124,9 +126,10 @@ func splitFunc(ctx *flags.Context) error {
 				splits[i+1] = head
 			}
 
+			indexed := gts.IndexFeatures(seq)
 			for i, tail := range splits[1:] {
 				head := splits[i]
-				sub := gts.Slice(seq, head, tail)
+				sub := gts.Slice(indexed, head, tail)
 				sub = gts.WithTopology(sub, gts.Linear)
 				if _, err := writer.WriteSeq(sub); err != nil {
 					return ctx.Raise(err)
@@ -143,5 +146,5 @@ func splitFunc(ctx *flags.Context) error {
 		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
 	}
 
-	return nil
+	return ctx.Raise(closeWriter(writer, buffer))
 }
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-gts/flags"
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/cmd"
+	"github.com/go-gts/gts/seqio"
+)
+
+func init() {
+	flags.Register("translate", "translate the coding sequences into amino acid sequences", translateFunc)
+}
+
+func translationName(seq gts.Sequence, f gts.Feature) string {
+	for _, name := range []string{"protein_id", "locus_tag", "gene"} {
+		if values := f.Props.Get(name); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return fmt.Sprintf("%s:%s", seqio.SequenceID(seq), f.Loc)
+}
+
+func translateFunc(ctx *flags.Context) error {
+	h := newHash()
+	pos, opt := flags.Flags()
+
+	selectors := pos.Extra("selector", "feature selector (syntax: [feature_key][/[qualifier1][=regexp1]][/[qualifier2][=regexp2]]...)")
+
+	seqinPath := new(string)
+	*seqinPath = "-"
+	if cmd.IsTerminal(os.Stdin.Fd()) {
+		seqinPath = pos.String("seqin", "input sequence file (may be omitted if standard input is provided)")
+	}
+
+	nocache := opt.Switch(0, "no-cache", "do not use or create cache")
+	seqoutPath := opt.String('o', "output", "-", "output sequence file (specifying `-` will force standard output)")
+	table := opt.Int('t', "table", 1, "genetic code table to use for features without a /transl_table qualifier")
+	check := opt.Switch('c', "check", "report whether the /translation qualifiers match the computed translations")
+
+	if err := ctx.Parse(pos, opt); err != nil {
+		return err
+	}
+
+	if _, ok := gts.GeneticCodes[*table]; !ok {
+		return ctx.Raise(fmt.Errorf("unknown genetic code table: %d", *table))
+	}
+
+	if len(*selectors) == 0 {
+		*selectors = append(*selectors, "CDS")
+	}
+
+	sort.Strings(*selectors)
+
+	filters := make([]gts.Filter, len(*selectors))
+	for i, selector := range *selectors {
+		f, err := gts.Selector(selector)
+		if err != nil {
+			return ctx.Raise(fmt.Errorf("invalid selector syntax: %v", err))
+		}
+		filters[i] = f
+	}
+	filter := gts.Or(filters...)
+
+	d, err := newIODelegate(*seqinPath, *seqoutPath)
+	if err != nil {
+		return ctx.Raise(err)
+	}
+	defer d.Close()
+
+	if !*nocache {
+		data := encodePayload([]tuple{
+			{"command", strings.Join(ctx.Name, "-")},
+			{"version", gts.Version.String()},
+			{"selectors", *selectors},
+			{"table", *table},
+			{"check", *check},
+		})
+
+		ok, err := d.TryCache(h, data)
+		if ok || err != nil {
+			return ctx.Raise(err)
+		}
+	}
+
+	scanner := seqio.NewAutoScanner(d)
+	buffer := bufio.NewWriter(d)
+	writer := seqio.NewWriter(buffer, seqio.FastaFile)
+
+	for scanner.Scan() {
+		seq := scanner.Value()
+
+		for _, f := range seq.Features().Filter(filter) {
+			name := translationName(seq, f)
+			out, err := gts.Translate(seq, f, *table)
+			if err != nil {
+				return ctx.Raise(fmt.Errorf("failed to translate %s: %v", name, err))
+			}
+
+			if *check {
+				status := "missing"
+				if values := f.Props.Get("translation"); len(values) > 0 {
+					status = "mismatch"
+					exp := strings.Join(strings.Fields(values[0]), "")
+					if strings.EqualFold(exp, string(out.Bytes())) {
+						status = "match"
+					}
+				}
+				if _, err := io.WriteString(buffer, fmt.Sprintf("%s\t%s\n", name, status)); err != nil {
+					return ctx.Raise(err)
+				}
+			} else {
+				desc := name
+				if values := f.Props.Get("product"); len(values) > 0 {
+					desc = fmt.Sprintf("%s %s", name, values[0])
+				}
+				out = gts.WithInfo(out, desc)
+				if _, err := writer.WriteSeq(out); err != nil {
+					return ctx.Raise(err)
+				}
+			}
+		}
+
+		if err := buffer.Flush(); err != nil {
+			return ctx.Raise(err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return ctx.Raise(fmt.Errorf("encountered error in scanner: %v", err))
+	}
+
+	return ctx.Raise(closeWriter(writer, buffer))
+}
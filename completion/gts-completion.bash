@@ -1,6 +1,6 @@
 _gts_annotate()
 {
-    opts="-h --help --version -F --format --no-cache -o --output"
+    opts="-h --help --version -F --format -j --jobs --no-cache -o --output"
     local cur="${COMP_WORDS[$COMP_CWORD]}"
     case "$cur" in
         -*)
@@ -157,9 +157,31 @@ _gts_clear()
     esac
 }
 
+_gts_combine()
+{
+    opts="-h --help --version -F --format -k --key --no-cache -o --output -q --qualifier -r --replace"
+    local cur="${COMP_WORDS[$COMP_CWORD]}"
+    case "$cur" in
+        -*)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do
+                COMPREPLY+=("$line")
+            done < <(compgen -W "$opts" -- "$cur")
+            ;;
+        *)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do 
+                COMPREPLY+=("$line")
+            done < <(compgen -f -- "$cur")
+            ;;
+    esac
+}
+
 _gts_complement()
 {
-    opts="-h --help --version -F --format --no-cache -o --output"
+    opts="-h --help --version -F --format -j --jobs --no-cache -o --output"
     local cur="${COMP_WORDS[$COMP_CWORD]}"
     case "$cur" in
         -*)
@@ -223,9 +245,97 @@ _gts_delete()
     esac
 }
 
+_gts_diff()
+{
+    opts="-h --help --version -j --json -o --output"
+    local cur="${COMP_WORDS[$COMP_CWORD]}"
+    case "$cur" in
+        -*)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do
+                COMPREPLY+=("$line")
+            done < <(compgen -W "$opts" -- "$cur")
+            ;;
+        *)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do 
+                COMPREPLY+=("$line")
+            done < <(compgen -f -- "$cur")
+            ;;
+    esac
+}
+
+_gts_digest()
+{
+    opts="-h --help --version -f --fragments -F --format -k --key --no-cache -o --output -q --qualifier"
+    local cur="${COMP_WORDS[$COMP_CWORD]}"
+    case "$cur" in
+        -*)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do
+                COMPREPLY+=("$line")
+            done < <(compgen -W "$opts" -- "$cur")
+            ;;
+        *)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do 
+                COMPREPLY+=("$line")
+            done < <(compgen -f -- "$cur")
+            ;;
+    esac
+}
+
 _gts_extract()
 {
-    opts="-h --help --version -F --format --no-cache -o --output"
+    opts="-h --help --version -F --format -j --jobs --no-cache -o --output --remote -v --invert-region"
+    local cur="${COMP_WORDS[$COMP_CWORD]}"
+    case "$cur" in
+        -*)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do
+                COMPREPLY+=("$line")
+            done < <(compgen -W "$opts" -- "$cur")
+            ;;
+        *)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do 
+                COMPREPLY+=("$line")
+            done < <(compgen -f -- "$cur")
+            ;;
+    esac
+}
+
+_gts_fetch()
+{
+    opts="-h --help --version -F --format -i --index -o --output"
+    local cur="${COMP_WORDS[$COMP_CWORD]}"
+    case "$cur" in
+        -*)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do
+                COMPREPLY+=("$line")
+            done < <(compgen -W "$opts" -- "$cur")
+            ;;
+        *)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do 
+                COMPREPLY+=("$line")
+            done < <(compgen -f -- "$cur")
+            ;;
+    esac
+}
+
+_gts_index()
+{
+    opts="-h --help --version -f --faidx -o --output"
     local cur="${COMP_WORDS[$COMP_CWORD]}"
     case "$cur" in
         -*)
@@ -333,9 +443,75 @@ _gts_length()
     esac
 }
 
+_gts_orf()
+{
+    opts="-h --help --version -F --format -k --key -m --min-length --no-cache --no-complement -o --output -q --qualifier -s --start -t --table"
+    local cur="${COMP_WORDS[$COMP_CWORD]}"
+    case "$cur" in
+        -*)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do
+                COMPREPLY+=("$line")
+            done < <(compgen -W "$opts" -- "$cur")
+            ;;
+        *)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do 
+                COMPREPLY+=("$line")
+            done < <(compgen -f -- "$cur")
+            ;;
+    esac
+}
+
+_gts_patch()
+{
+    opts="-h --help --version -F --format --no-cache -o --output -r --report -s --split"
+    local cur="${COMP_WORDS[$COMP_CWORD]}"
+    case "$cur" in
+        -*)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do
+                COMPREPLY+=("$line")
+            done < <(compgen -W "$opts" -- "$cur")
+            ;;
+        *)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do 
+                COMPREPLY+=("$line")
+            done < <(compgen -f -- "$cur")
+            ;;
+    esac
+}
+
+_gts_pcr()
+{
+    opts="-h --help --version -a --anchor -F --format -k --key -m --mismatches --max-size --no-cache -o --output -r --report"
+    local cur="${COMP_WORDS[$COMP_CWORD]}"
+    case "$cur" in
+        -*)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do
+                COMPREPLY+=("$line")
+            done < <(compgen -W "$opts" -- "$cur")
+            ;;
+        *)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do 
+                COMPREPLY+=("$line")
+            done < <(compgen -f -- "$cur")
+            ;;
+    esac
+}
+
 _gts_pick()
 {
-    opts="-h --help --version -f --feature -F --format --no-cache -o --output"
+    opts="-h --help --version -d --definition -f --feature -F --format --id-file -i --index -l --length -m --molecule --no-cache --organism -o --output -t --topology"
     local cur="${COMP_WORDS[$COMP_CWORD]}"
     case "$cur" in
         -*)
@@ -445,7 +621,7 @@ _gts_rotate()
 
 _gts_search()
 {
-    opts="-h --help --version -e --exact -F --format -k --key --no-cache --no-complement -o --output -q --qualifier"
+    opts="-h --help --version -e --exact -F --format --indels -j --jobs -k --key -m --mismatches --no-cache --no-complement -o --output -q --qualifier"
     local cur="${COMP_WORDS[$COMP_CWORD]}"
     case "$cur" in
         -*)
@@ -467,7 +643,7 @@ _gts_search()
 
 _gts_select()
 {
-    opts="-h --help --version -F --format --no-cache -o --output -s --strand -v --invert-match"
+    opts="-h --help --version -F --format -j --jobs --no-cache -o --output -s --strand -v --invert-match"
     local cur="${COMP_WORDS[$COMP_CWORD]}"
     case "$cur" in
         -*)
@@ -489,7 +665,7 @@ _gts_select()
 
 _gts_sort()
 {
-    opts="-h --help --version -F --format --no-cache -o --output -r --reverse"
+    opts="-h --help --version -b --buffer -F --format -k --key --no-cache -o --output -r --reverse"
     local cur="${COMP_WORDS[$COMP_CWORD]}"
     case "$cur" in
         -*)
@@ -553,9 +729,31 @@ _gts_summary()
     esac
 }
 
+_gts_translate()
+{
+    opts="-h --help --version -c --check --no-cache -o --output -t --table"
+    local cur="${COMP_WORDS[$COMP_CWORD]}"
+    case "$cur" in
+        -*)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do
+                COMPREPLY+=("$line")
+            done < <(compgen -W "$opts" -- "$cur")
+            ;;
+        *)
+            COMPREPLY=()
+            while IFS='' read -r line
+            do 
+                COMPREPLY+=("$line")
+            done < <(compgen -f -- "$cur")
+            ;;
+    esac
+}
+
 _gts()
 {
-    cmds="-h --help --version annotate cache clear complement define delete extract infix insert join length pick query repair reverse rotate search select sort split summary"
+    cmds="-h --help --version annotate cache clear combine complement define delete diff digest extract fetch index infix insert join length orf patch pcr pick query repair reverse rotate search select sort split summary translate"
     local i=0 cmd
 
     while [[ "$i" -lt "$COMP_CWORD" ]]
@@ -598,14 +796,22 @@ _gts()
         annotate)   _gts_annotate ;;
         cache)      _gts_cache ;;
         clear)      _gts_clear ;;
+        combine)    _gts_combine ;;
         complement) _gts_complement ;;
         define)     _gts_define ;;
         delete)     _gts_delete ;;
+        diff)       _gts_diff ;;
+        digest)     _gts_digest ;;
         extract)    _gts_extract ;;
+        fetch)      _gts_fetch ;;
+        index)      _gts_index ;;
         infix)      _gts_infix ;;
         insert)     _gts_insert ;;
         join)       _gts_join ;;
         length)     _gts_length ;;
+        orf)        _gts_orf ;;
+        patch)      _gts_patch ;;
+        pcr)        _gts_pcr ;;
         pick)       _gts_pick ;;
         query)      _gts_query ;;
         repair)     _gts_repair ;;
@@ -616,6 +822,7 @@ _gts()
         sort)       _gts_sort ;;
         split)      _gts_split ;;
         summary)    _gts_summary ;;
+        translate)  _gts_translate ;;
         *) ;;
     esac
 }
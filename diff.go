@@ -0,0 +1,335 @@
+package gts
+
+import (
+	"bytes"
+	"reflect"
+
+	"github.com/go-gts/gts/internal/diff"
+)
+
+// EditType represents the type of a difference between two sequences.
+type EditType int
+
+const (
+	// Substitution represents a region of bases replaced with other bases.
+	Substitution EditType = iota
+
+	// Insertion represents bases present only in the new sequence.
+	Insertion
+
+	// Deletion represents bases present only in the old sequence.
+	Deletion
+)
+
+// String satisfies the fmt.Stringer interface.
+func (t EditType) String() string {
+	switch t {
+	case Substitution:
+		return "substitution"
+	case Insertion:
+		return "insertion"
+	case Deletion:
+		return "deletion"
+	default:
+		return ""
+	}
+}
+
+// BaseEdit represents a base level difference between two sequences. Loc is
+// the location of the edit in the old sequence and Pos is the position of the
+// edit in the new sequence. Old and New are the bases removed from the old
+// sequence and added to the new sequence respectively.
+type BaseEdit struct {
+	Loc Location
+	Pos int
+	Old []byte
+	New []byte
+}
+
+// Type returns the type of the edit.
+func (edit BaseEdit) Type() EditType {
+	switch {
+	case len(edit.Old) == 0:
+		return Insertion
+	case len(edit.New) == 0:
+		return Deletion
+	default:
+		return Substitution
+	}
+}
+
+func toRunes(p []byte) []rune {
+	rr := make([]rune, len(p))
+	for i, c := range p {
+		rr[i] = rune(c)
+	}
+	return rr
+}
+
+// editSpan represents the region [head, tail) of the old sequence replaced by
+// the region [pos, end) of the new sequence.
+type editSpan struct {
+	head, tail int
+	pos, end   int
+}
+
+func (s editSpan) insertion() bool {
+	return s.head == s.tail && s.pos < s.end
+}
+
+func (s editSpan) deletion() bool {
+	return s.pos == s.end && s.head < s.tail
+}
+
+// joinSpans tries to express a pair of insertions or deletions separated by a
+// common region as a single insertion or deletion.
+func joinSpans(a, b editSpan, lp, lq []byte) (editSpan, bool) {
+	switch {
+	case a.deletion() && b.deletion():
+		kept, region := lp[a.tail:b.head], lp[a.head:b.tail]
+		if bytes.HasSuffix(region, kept) {
+			return editSpan{a.head, b.tail - len(kept), a.pos, a.pos}, true
+		}
+		if bytes.HasPrefix(region, kept) {
+			return editSpan{a.head + len(kept), b.tail, b.pos, b.pos}, true
+		}
+	case a.insertion() && b.insertion():
+		kept, region := lq[a.end:b.pos], lq[a.pos:b.end]
+		if bytes.HasSuffix(region, kept) {
+			return editSpan{a.head, a.head, a.pos, b.end - len(kept)}, true
+		}
+		if bytes.HasPrefix(region, kept) {
+			return editSpan{b.head, b.head, a.pos + len(kept), b.end}, true
+		}
+	}
+	return editSpan{}, false
+}
+
+// editLocation returns the location of an edit spanning the given number of
+// bases from the given position.
+func editLocation(pos, length int) Location {
+	switch length {
+	case 0:
+		return Between(pos)
+	case 1:
+		return Point(pos)
+	default:
+		return Range(pos, pos+length)
+	}
+}
+
+// DiffBases returns the base level differences between the old and new
+// sequences, computed from the shortest edit script of the two sequences. The
+// bases are compared case insensitively. Insertions and deletions which are
+// split by the edit script are joined where possible, and are shifted to their
+// leftmost equivalent positions. Adjacent edits are reported as a single
+// substitution.
+func DiffBases(prev, next Sequence) []BaseEdit {
+	p, q := prev.Bytes(), next.Bytes()
+	lp, lq := bytes.ToLower(p), bytes.ToLower(q)
+	ops := diff.Diff(string(toRunes(lp)), string(toRunes(lq)))
+
+	spans := []editSpan{}
+	i, j, open := 0, 0, false
+	for _, op := range ops {
+		if _, ok := op.(diff.Common); ok {
+			i, j, open = i+1, j+1, false
+			continue
+		}
+		if !open {
+			spans = append(spans, editSpan{i, i, j, j})
+			open = true
+		}
+		s := &spans[len(spans)-1]
+		switch op.(type) {
+		case diff.Delete:
+			i++
+			s.tail = i
+		case diff.Insert:
+			j++
+			s.end = j
+		}
+	}
+
+	merged := []editSpan{}
+	for _, s := range spans {
+		for k := len(merged) - 1; k >= 0; k-- {
+			t, ok := joinSpans(merged[k], s, lp, lq)
+			if !ok {
+				break
+			}
+			merged, s = merged[:k], t
+		}
+
+		lower := 0
+		if len(merged) > 0 {
+			lower = merged[len(merged)-1].tail
+		}
+
+		for s.head > lower && (s.insertion() || s.deletion()) {
+			c := lp[s.tail-1]
+			if s.head == s.tail {
+				c = lq[s.end-1]
+			}
+			if lp[s.head-1] != c {
+				break
+			}
+			s = editSpan{s.head - 1, s.tail - 1, s.pos - 1, s.end - 1}
+		}
+
+		if k := len(merged) - 1; k >= 0 && merged[k].tail == s.head {
+			merged[k].tail, merged[k].end = s.tail, s.end
+			continue
+		}
+		merged = append(merged, s)
+	}
+
+	edits := make([]BaseEdit, len(merged))
+	for k, s := range merged {
+		loc := editLocation(s.head, s.tail-s.head)
+		edits[k] = BaseEdit{loc, s.pos, p[s.head:s.tail], q[s.pos:s.end]}
+	}
+
+	return edits
+}
+
+// FeatureChange represents the type of a difference between two features.
+type FeatureChange int
+
+const (
+	// FeatureAdded represents a feature present only in the new sequence.
+	FeatureAdded FeatureChange = iota
+
+	// FeatureRemoved represents a feature present only in the old sequence.
+	FeatureRemoved
+
+	// FeatureMoved represents a feature with a changed location.
+	FeatureMoved
+
+	// FeatureModified represents a feature with changed qualifiers.
+	FeatureModified
+)
+
+// String satisfies the fmt.Stringer interface.
+func (change FeatureChange) String() string {
+	switch change {
+	case FeatureAdded:
+		return "added"
+	case FeatureRemoved:
+		return "removed"
+	case FeatureMoved:
+		return "moved"
+	case FeatureModified:
+		return "modified"
+	default:
+		return ""
+	}
+}
+
+// QualifierDiff represents a difference in the values of a qualifier.
+type QualifierDiff struct {
+	Name string
+	Old  []string
+	New  []string
+}
+
+// FeatureDiff represents a difference between the features of two sequences.
+// Old is the zero Feature for an added feature, and New is the zero Feature
+// for a removed feature.
+type FeatureDiff struct {
+	Change     FeatureChange
+	Old        Feature
+	New        Feature
+	Qualifiers []QualifierDiff
+}
+
+func diffProps(prev, next Props) []QualifierDiff {
+	keys := prev.Keys()
+	for _, key := range next.Keys() {
+		if !prev.Has(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	diffs := []QualifierDiff{}
+	for _, key := range keys {
+		a, b := prev.Get(key), next.Get(key)
+		if !reflect.DeepEqual(a, b) {
+			diffs = append(diffs, QualifierDiff{key, a, b})
+		}
+	}
+	return diffs
+}
+
+// mapLocation returns the location in the new sequence corresponding to the
+// given location in the old sequence by applying the edits in order.
+func mapLocation(loc Location, edits []BaseEdit) Location {
+	for _, edit := range edits {
+		switch delta := len(edit.New) - len(edit.Old); {
+		case delta < 0:
+			loc = loc.Expand(edit.Pos+len(edit.New), delta)
+		case delta > 0:
+			loc = loc.Expand(edit.Pos+len(edit.Old), delta)
+		}
+	}
+	return loc
+}
+
+// DiffFeatures returns the differences between the features of the old and
+// new sequences given the base level edits between the two sequences. The
+// location of each old feature is first mapped onto the new sequence through
+// the edits. Features with the same key and mapped location but different
+// qualifiers are reported as modified, and features with the same key and
+// qualifiers but a different location are reported as moved. The remaining
+// features are reported as either removed or added.
+func DiffFeatures(prev, next FeatureSlice, edits []BaseEdit) []FeatureDiff {
+	locs := make([]string, len(prev))
+	for i, f := range prev {
+		locs[i] = mapLocation(f.Loc, edits).String()
+	}
+
+	paired := make([]bool, len(next))
+	matched := make([]FeatureDiff, len(prev))
+	found := make([]bool, len(prev))
+
+	pair := func(match func(i, j int) bool, change FeatureChange) {
+		for i, f := range prev {
+			if found[i] {
+				continue
+			}
+			for j, g := range next {
+				if paired[j] || f.Key != g.Key || !match(i, j) {
+					continue
+				}
+				found[i], paired[j] = true, true
+				matched[i] = FeatureDiff{change, f, g, diffProps(f.Props, g.Props)}
+				break
+			}
+		}
+	}
+
+	sameLoc := func(i, j int) bool { return locs[i] == next[j].Loc.String() }
+	sameProps := func(i, j int) bool { return reflect.DeepEqual(prev[i].Props, next[j].Props) }
+
+	unchanged := FeatureChange(-1)
+	pair(func(i, j int) bool { return sameLoc(i, j) && sameProps(i, j) }, unchanged)
+	pair(sameLoc, FeatureModified)
+	pair(sameProps, FeatureMoved)
+
+	diffs := []FeatureDiff{}
+	for i, f := range prev {
+		switch {
+		case !found[i]:
+			diffs = append(diffs, FeatureDiff{FeatureRemoved, f, Feature{}, nil})
+		case matched[i].Change != unchanged:
+			diffs = append(diffs, matched[i])
+		}
+	}
+	for j, g := range next {
+		if !paired[j] {
+			diffs = append(diffs, FeatureDiff{FeatureAdded, Feature{}, g, nil})
+		}
+	}
+
+	return diffs
+}
@@ -0,0 +1,99 @@
+package gts
+
+import (
+	"testing"
+
+	"github.com/go-gts/gts/internal/testutils"
+)
+
+var diffBasesTests = []struct {
+	prev, next string
+	out        []BaseEdit
+}{
+	{"aaccggtt", "aaccggtt", []BaseEdit{}},
+	{"aaccggtt", "AACCGGTT", []BaseEdit{}},
+	{"aacgtt", "aaggtt", []BaseEdit{
+		{Point(2), 2, []byte("c"), []byte("g")},
+	}},
+	{"aaccgg", "aaccttgg", []BaseEdit{
+		{Between(4), 4, []byte{}, []byte("tt")},
+	}},
+	{"aaccttgg", "aaccgg", []BaseEdit{
+		{Range(4, 6), 4, []byte("tt"), []byte{}},
+	}},
+	{"aattcc", "aatttcc", []BaseEdit{
+		{Between(2), 2, []byte{}, []byte("t")},
+	}},
+	{"acacgg", "acacacgg", []BaseEdit{
+		{Between(0), 0, []byte{}, []byte("ac")},
+	}},
+	{"aatttcc", "aattcc", []BaseEdit{
+		{Point(2), 2, []byte("t"), []byte{}},
+	}},
+	{"ttacgtacgtacgt", "ttacgtacgt", []BaseEdit{
+		{Range(1, 5), 1, []byte("tacg"), []byte{}},
+	}},
+	{"aacgttcc", "aaggtt", []BaseEdit{
+		{Point(2), 2, []byte("c"), []byte("g")},
+		{Range(6, 8), 6, []byte("cc"), []byte{}},
+	}},
+}
+
+func TestDiffBases(t *testing.T) {
+	for _, tt := range diffBasesTests {
+		prev := New(nil, nil, []byte(tt.prev))
+		next := New(nil, nil, []byte(tt.next))
+		out := DiffBases(prev, next)
+		testutils.Equals(t, out, tt.out)
+	}
+
+	edit := BaseEdit{Point(0), 0, []byte("a"), []byte("c")}
+	testutils.Equals(t, edit.Type(), Substitution)
+	testutils.Equals(t, edit.Type().String(), "substitution")
+	edit = BaseEdit{Between(0), 0, nil, []byte("c")}
+	testutils.Equals(t, edit.Type(), Insertion)
+	testutils.Equals(t, edit.Type().String(), "insertion")
+	edit = BaseEdit{Point(0), 0, []byte("a"), nil}
+	testutils.Equals(t, edit.Type(), Deletion)
+	testutils.Equals(t, edit.Type().String(), "deletion")
+}
+
+func TestDiffFeatures(t *testing.T) {
+	prev := New(nil, FeatureSlice{
+		NewFeature("gene", Range(0, 2), Props{{"note", "a"}}),
+		NewFeature("CDS", Range(2, 6), Props{{"note", "b"}}),
+		NewFeature("misc_feature", Range(6, 8), Props{{"note", "d"}}),
+		NewFeature("repeat_region", Range(8, 10), Props{}),
+	}, []byte("aaccggttaa"))
+
+	next := New(nil, FeatureSlice{
+		NewFeature("gene", Range(0, 2), Props{{"note", "a"}}),
+		NewFeature("primer_bind", Range(0, 1), Props{}),
+		NewFeature("CDS", Range(5, 9), Props{{"note", "c"}, {"gene", "e"}}),
+		NewFeature("misc_feature", Range(0, 3), Props{{"note", "d"}}),
+	}, []byte("aatttccggttaa"))
+
+	edits := DiffBases(prev, next)
+	testutils.Equals(t, edits, []BaseEdit{
+		{Between(2), 2, []byte{}, []byte("ttt")},
+	})
+
+	pp, nn := prev.Features(), next.Features()
+	out := DiffFeatures(pp, nn, edits)
+	exp := []FeatureDiff{
+		{FeatureModified, pp[1], nn[2], []QualifierDiff{
+			{"note", []string{"b"}, []string{"c"}},
+			{"gene", nil, []string{"e"}},
+		}},
+		{FeatureMoved, pp[2], nn[3], []QualifierDiff{}},
+		{FeatureRemoved, pp[3], Feature{}, nil},
+		{FeatureAdded, Feature{}, nn[1], nil},
+	}
+	testutils.Equals(t, out, exp)
+
+	changes := []FeatureChange{FeatureAdded, FeatureRemoved, FeatureMoved, FeatureModified}
+	names := []string{"added", "removed", "moved", "modified"}
+	for i, change := range changes {
+		testutils.Equals(t, change.String(), names[i])
+	}
+}
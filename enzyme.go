@@ -0,0 +1,282 @@
+package gts
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Enzyme represents a restriction enzyme. Top and Bottom are the positions at
+// which the enzyme cuts the top and bottom strands respectively, relative to
+// the start of the recognition site on the top strand.
+type Enzyme struct {
+	Name   string
+	Site   string
+	Top    int
+	Bottom int
+}
+
+// AsEnzyme creates an Enzyme from a recognition site in REBASE notation. The
+// cut positions are either given with a caret within the site (e.g. `G^AATTC`)
+// or with the offsets from the end of the site in parentheses (e.g.
+// `GGTCTC(1/5)`). In the former case the enzyme is assumed to cut both strands
+// symmetrically.
+func AsEnzyme(name, s string) (Enzyme, error) {
+	if i := strings.IndexByte(s, '('); i >= 0 {
+		site := s[:i]
+		if !strings.HasSuffix(s, ")") {
+			return Enzyme{}, fmt.Errorf("malformed recognition site %q: expected `)`", s)
+		}
+		offsets := strings.Split(s[i+1:len(s)-1], "/")
+		if len(offsets) != 2 {
+			return Enzyme{}, fmt.Errorf("malformed recognition site %q: expected two cut offsets", s)
+		}
+		top, err := strconv.Atoi(offsets[0])
+		if err != nil {
+			return Enzyme{}, fmt.Errorf("malformed recognition site %q: %v", s, err)
+		}
+		bottom, err := strconv.Atoi(offsets[1])
+		if err != nil {
+			return Enzyme{}, fmt.Errorf("malformed recognition site %q: %v", s, err)
+		}
+		return Enzyme{name, site, len(site) + top, len(site) + bottom}, nil
+	}
+
+	i := strings.IndexByte(s, '^')
+	if i < 0 || strings.Count(s, "^") != 1 {
+		return Enzyme{}, fmt.Errorf("malformed recognition site %q: expected a single `^`", s)
+	}
+	site := s[:i] + s[i+1:]
+	return Enzyme{name, site, i, len(site) - i}, nil
+}
+
+// Overhang returns the length of the overhang left by the enzyme. The value
+// is positive for 5' overhangs, negative for 3' overhangs, and zero for blunt
+// ends.
+func (e Enzyme) Overhang() int {
+	return e.Bottom - e.Top
+}
+
+// enzymeTable lists the bundled restriction enzymes in REBASE notation.
+const enzymeTable = `
+AarI	CACCTGC(4/8)
+AatII	GACGT^C
+AflII	C^TTAAG
+AgeI	A^CCGGT
+AhdI	GACNNN^NNGTC
+AluI	AG^CT
+ApaI	GGGCC^C
+AscI	GG^CGCGCC
+AvrII	C^CTAGG
+BamHI	G^GATCC
+BbsI	GAAGAC(2/6)
+BglI	GCCNNNN^NGGC
+BglII	A^GATCT
+BsaAI	YAC^GTR
+BsaI	GGTCTC(1/5)
+BsiWI	C^GTACG
+BsmBI	CGTCTC(1/5)
+BsmI	GAATGC(1/-1)
+BspHI	T^CATGA
+BsrGI	T^GTACA
+BstBI	TT^CGAA
+BstXI	CCANNNNN^NTGG
+ClaI	AT^CGAT
+CviQI	G^TAC
+DdeI	C^TNAG
+DraI	TTT^AAA
+EagI	C^GGCCG
+EarI	CTCTTC(1/4)
+EcoNI	CCTNN^NNNAGG
+EcoRI	G^AATTC
+EcoRV	GAT^ATC
+Esp3I	CGTCTC(1/5)
+FseI	GGCCGG^CC
+HaeIII	GG^CC
+HincII	GTY^RAC
+HindIII	A^AGCTT
+HinfI	G^ANTC
+HpaI	GTT^AAC
+HpaII	C^CGG
+KpnI	GGTAC^C
+MboI	^GATC
+MfeI	C^AATTG
+MluI	A^CGCGT
+MseI	T^TAA
+MspI	C^CGG
+NcoI	C^CATGG
+NdeI	CA^TATG
+NheI	G^CTAGC
+NlaIII	CATG^
+NotI	GC^GGCCGC
+NruI	TCG^CGA
+NsiI	ATGCA^T
+PacI	TTAAT^TAA
+PciI	A^CATGT
+PmeI	GTTT^AAAC
+PstI	CTGCA^G
+PvuII	CAG^CTG
+RsaI	GT^AC
+SacI	GAGCT^C
+SacII	CCGC^GG
+SalI	G^TCGAC
+SapI	GCTCTTC(1/4)
+Sau3AI	^GATC
+ScaI	AGT^ACT
+SfiI	GGCCNNNN^NGGCC
+SmaI	CCC^GGG
+SnaBI	TAC^GTA
+SpeI	A^CTAGT
+SphI	GCATG^C
+StuI	AGG^CCT
+SwaI	ATTT^AAAT
+TaqI	T^CGA
+XbaI	T^CTAGA
+XhoI	C^TCGAG
+XmaI	C^CCGGG
+XmnI	GAANN^NNTTC
+`
+
+// Enzymes contains the bundled restriction enzymes keyed by their names.
+var Enzymes = func() map[string]Enzyme {
+	enzymes := make(map[string]Enzyme)
+	scanner := bufio.NewScanner(strings.NewReader(enzymeTable))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		e, err := AsEnzyme(fields[0], fields[1])
+		if err != nil {
+			panic(err)
+		}
+		enzymes[e.Name] = e
+	}
+	return enzymes
+}()
+
+// LookupEnzyme returns the bundled restriction enzyme with the given name.
+// The name is matched case insensitively.
+func LookupEnzyme(name string) (Enzyme, error) {
+	if e, ok := Enzymes[name]; ok {
+		return e, nil
+	}
+	for key, e := range Enzymes {
+		if strings.EqualFold(key, name) {
+			return e, nil
+		}
+	}
+	return Enzyme{}, fmt.Errorf("unknown restriction enzyme: %q", name)
+}
+
+// RestrictionSite represents a recognition site of a restriction enzyme in a
+// sequence. Top and Bottom are the positions at which the enzyme cuts the top
+// and bottom strands of the sequence respectively.
+type RestrictionSite struct {
+	Enzyme Enzyme
+	Loc    Location
+	Top    int
+	Bottom int
+}
+
+func wrapPosition(i, length int) int {
+	return ((i % length) + length) % length
+}
+
+// FindRestrictionSites returns the recognition sites of the given enzymes
+// found on both strands of the sequence, sorted by the cut positions on the
+// top strand. If the sequence is linear, sites which are cut beyond the ends
+// of the sequence are ignored. If the sequence is circular, sites spanning
+// across the origin will also be reported.
+func FindRestrictionSites(seq Sequence, enzymes ...Enzyme) []RestrictionSite {
+	n := Len(seq)
+	circular := TopologyOf(seq) == Circular
+
+	sites := []RestrictionSite{}
+	add := func(e Enzyme, head, tail, top, bottom int, complement bool) {
+		if circular {
+			top, bottom = wrapPosition(top, n), wrapPosition(bottom, n)
+		} else if top < 0 || n < top || bottom < 0 || n < bottom {
+			return
+		}
+		var loc Location = Range(head, tail)
+		if circular {
+			loc = loc.Normalize(n)
+		}
+		if complement {
+			loc = loc.Complement()
+		}
+		sites = append(sites, RestrictionSite{e, loc, top, bottom})
+	}
+
+	for _, e := range enzymes {
+		site := New(nil, nil, []byte(strings.ToLower(e.Site)))
+		for _, s := range Match(seq, site) {
+			head, tail := Unpack(s)
+			add(e, head, tail, head+e.Top, head+e.Bottom, false)
+		}
+
+		cmp := Reverse(Complement(site))
+		if string(cmp.Bytes()) == string(site.Bytes()) {
+			continue
+		}
+		for _, s := range Match(seq, cmp) {
+			head, tail := Unpack(s)
+			add(e, head, tail, tail-e.Bottom, tail-e.Top, true)
+		}
+	}
+
+	sort.SliceStable(sites, func(i, j int) bool {
+		return sites[i].Top < sites[j].Top
+	})
+
+	return sites
+}
+
+// Digest the sequence with the given enzymes and return the resulting
+// fragments in the order of their positions. The fragments are delimited by
+// the cut positions on the top strand. If the sequence is circular, the
+// fragments will be linear and the fragment spanning across the origin will be
+// joined.
+func Digest(seq Sequence, enzymes ...Enzyme) []Sequence {
+	n := Len(seq)
+	circular := TopologyOf(seq) == Circular
+
+	cuts := []int{}
+	for _, site := range FindRestrictionSites(seq, enzymes...) {
+		cut := site.Top
+		if !circular && (cut == 0 || cut == n) {
+			continue
+		}
+		if len(cuts) == 0 || cuts[len(cuts)-1] != cut {
+			cuts = append(cuts, cut)
+		}
+	}
+
+	if len(cuts) == 0 {
+		return []Sequence{seq}
+	}
+
+	if !circular {
+		bounds := append(append([]int{0}, cuts...), n)
+		frags := make([]Sequence, len(bounds)-1)
+		for i := range frags {
+			frags[i] = Slice(seq, bounds[i], bounds[i+1])
+		}
+		return frags
+	}
+
+	if len(cuts) == 1 {
+		frag := Rotate(seq, -cuts[0])
+		return []Sequence{WithTopology(frag, Linear)}
+	}
+
+	frags := make([]Sequence, len(cuts))
+	for i := range cuts {
+		frag := Slice(seq, cuts[i], cuts[(i+1)%len(cuts)])
+		frags[i] = WithTopology(frag, Linear)
+	}
+	return frags
+}
@@ -0,0 +1,129 @@
+package gts
+
+import (
+	"testing"
+
+	"github.com/go-gts/gts/internal/testutils"
+)
+
+var asEnzymeTests = []struct {
+	in  string
+	out Enzyme
+}{
+	{"G^AATTC", Enzyme{"", "GAATTC", 1, 5}},
+	{"CTGCA^G", Enzyme{"", "CTGCAG", 5, 1}},
+	{"CCC^GGG", Enzyme{"", "CCCGGG", 3, 3}},
+	{"^GATC", Enzyme{"", "GATC", 0, 4}},
+	{"GGTCTC(1/5)", Enzyme{"", "GGTCTC", 7, 11}},
+	{"GAATGC(1/-1)", Enzyme{"", "GAATGC", 7, 5}},
+}
+
+func TestAsEnzyme(t *testing.T) {
+	for _, tt := range asEnzymeTests {
+		out, err := AsEnzyme("", tt.in)
+		if err != nil {
+			t.Errorf("AsEnzyme(%q): %v", tt.in, err)
+			continue
+		}
+		testutils.Equals(t, out, tt.out)
+	}
+}
+
+var asEnzymeFailTests = []string{
+	"",
+	"GAATTC",
+	"G^AAT^TC",
+	"GGTCTC(1/5",
+	"GGTCTC(1)",
+	"GGTCTC(a/5)",
+	"GGTCTC(1/b)",
+}
+
+func TestAsEnzymeFail(t *testing.T) {
+	for _, in := range asEnzymeFailTests {
+		if _, err := AsEnzyme("", in); err == nil {
+			t.Errorf("expected error in AsEnzyme(%q)", in)
+		}
+	}
+}
+
+func TestEnzymes(t *testing.T) {
+	e, err := LookupEnzyme("EcoRI")
+	if err != nil {
+		t.Errorf("LookupEnzyme(%q): %v", "EcoRI", err)
+	}
+	testutils.Equals(t, e, Enzyme{"EcoRI", "GAATTC", 1, 5})
+	testutils.Equals(t, e.Overhang(), 4)
+
+	e, err = LookupEnzyme("psti")
+	if err != nil {
+		t.Errorf("LookupEnzyme(%q): %v", "psti", err)
+	}
+	testutils.Equals(t, e, Enzyme{"PstI", "CTGCAG", 5, 1})
+	testutils.Equals(t, e.Overhang(), -4)
+
+	if _, err := LookupEnzyme("FooI"); err == nil {
+		t.Errorf("expected error in LookupEnzyme(%q)", "FooI")
+	}
+}
+
+func TestFindRestrictionSites(t *testing.T) {
+	ecori, _ := LookupEnzyme("EcoRI")
+	bsai, _ := LookupEnzyme("BsaI")
+
+	p := []byte("ttgaattcaaggtctcaaaaaacccgagaccaa")
+	seq := newSeqWithTest(Linear, nil, p)
+	out := FindRestrictionSites(seq, ecori, bsai)
+	exp := []RestrictionSite{
+		{ecori, Range(2, 8), 3, 7},
+		{bsai, Range(10, 16), 17, 21},
+		{bsai, Range(25, 31).Complement(), 20, 24},
+	}
+	testutils.Equals(t, out, exp)
+
+	// The BsaI site cuts beyond the end of the sequence.
+	seq = newSeqWithTest(Linear, nil, p[:20])
+	out = FindRestrictionSites(seq, ecori, bsai)
+	exp = []RestrictionSite{{ecori, Range(2, 8), 3, 7}}
+	testutils.Equals(t, out, exp)
+
+	seq = newSeqWithTest(Linear, nil, []byte("ttcaaaaaaaaaaaagaa"))
+	testutils.Equals(t, FindRestrictionSites(seq, ecori), []RestrictionSite{})
+
+	seq = newSeqWithTest(Circular, nil, []byte("ttcaaaaaaaaaaaagaa"))
+	out = FindRestrictionSites(seq, ecori)
+	exp = []RestrictionSite{{ecori, Join(Range(15, 18), Range(0, 3)), 16, 2}}
+	testutils.Equals(t, out, exp)
+}
+
+func TestDigest(t *testing.T) {
+	ecori, _ := LookupEnzyme("EcoRI")
+	bamhi, _ := LookupEnzyme("BamHI")
+
+	p := []byte("aagaattcaaaggatcctt")
+
+	seq := newSeqWithTest(Linear, nil, p)
+	frags := Digest(seq, ecori, bamhi)
+	testutils.Equals(t, len(frags), 3)
+	testutils.Equals(t, string(frags[0].Bytes()), "aag")
+	testutils.Equals(t, string(frags[1].Bytes()), "aattcaaag")
+	testutils.Equals(t, string(frags[2].Bytes()), "gatcctt")
+
+	frags = Digest(seq, bamhi)
+	testutils.Equals(t, len(frags), 2)
+
+	seq = newSeqWithTest(Circular, nil, p)
+	frags = Digest(seq, ecori, bamhi)
+	testutils.Equals(t, len(frags), 2)
+	testutils.Equals(t, string(frags[0].Bytes()), "aattcaaag")
+	testutils.Equals(t, string(frags[1].Bytes()), "gatccttaag")
+	testutils.Equals(t, TopologyOf(frags[0]), Linear)
+
+	frags = Digest(seq, ecori)
+	testutils.Equals(t, len(frags), 1)
+	testutils.Equals(t, string(frags[0].Bytes()), "aattcaaaggatccttaag")
+	testutils.Equals(t, TopologyOf(frags[0]), Linear)
+
+	frags = Digest(seq)
+	testutils.Equals(t, frags, []Sequence{seq})
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
-	"strings"
 )
 
 type Feature struct {
@@ -185,52 +184,6 @@ func Qualifier(name, query string) (Filter, error) {
 	}, nil
 }
 
-func shiftSelector(s string) (string, string) {
-	esc := false
-	for i := 0; i < len(s); i++ {
-		switch s[i] {
-		case '\\':
-			esc = true
-		case '/':
-			if !esc {
-				return s[:i], s[i+1:]
-			}
-		default:
-			esc = false
-		}
-	}
-	return s, ""
-}
-
-func toQualifier(s string) (Filter, error) {
-	switch i := strings.IndexByte(s, '='); i {
-	case -1:
-		return Qualifier(s, "")
-	default:
-		return Qualifier(s[:i], s[i+1:])
-	}
-}
-
-// Selector generates a new Filter which will return true if a given Feature
-// satisfies the criteria specified by the selection string. A selector in GTS
-// is defined as follows:
-//   [feature_key]/qualifier_name=regexp[/qualifier_name=regexp]...
-// If the qualifier name is omitted, the values for every qualifier name will
-// be tested.
-func Selector(sel string) (Filter, error) {
-	head, tail := shiftSelector(sel)
-	filter := Key(head)
-	for tail != "" {
-		head, tail = shiftSelector(tail)
-		props, err := toQualifier(head)
-		if err != nil {
-			return FalseFilter, err
-		}
-		filter = And(filter, props)
-	}
-	return filter, nil
-}
-
 // ForwardStrand returns true if the feature strictly resides on the forward
 // strand.
 func ForwardStrand(f Feature) bool {
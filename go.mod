@@ -10,6 +10,7 @@ require (
 	github.com/go-pars/pars v1.1.6
 	github.com/go-test/deep v1.0.7
 	github.com/go-wrap/wrap v1.0.3
+	github.com/klauspost/compress v1.13.6
 	github.com/mattn/go-isatty v0.0.12
 	golang.org/x/sys v0.0.0-20210514084401-e8d321eab015 // indirect
 )
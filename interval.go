@@ -0,0 +1,349 @@
+package gts
+
+import "sort"
+
+// FeatureIndex represents an interval index over the features in a
+// FeatureSlice for querying the features by their positions. Each feature is
+// indexed by the span from the lowest to the highest position covered by its
+// location, and the spans are kept in an implicit interval tree built upon
+// the spans sorted by their starting positions.
+type FeatureIndex struct {
+	ff      FeatureSlice
+	order   []int
+	starts  []int
+	ends    []int
+	maxEnds []int
+	others  []int
+}
+
+// locationSpan returns the lowest and highest position covered by the given
+// location. If the location does not cover any position, ok will be false.
+func locationSpan(loc Location) (start, end int, ok bool) {
+	switch v := loc.(type) {
+	case Complemented:
+		return locationSpan(v.Location)
+
+	case locationSlice:
+		ll := v.slice()
+		if len(ll) == 0 {
+			return 0, 0, false
+		}
+		start, end, ok = locationSpan(ll[0])
+		for _, l := range ll[1:] {
+			s, e, tmp := locationSpan(l)
+			start, end, ok = Min(start, s), Max(end, e), ok && tmp
+		}
+		return start, end, ok
+
+	case contiguousLocation:
+		s, e := v.span()
+		if e < s {
+			s, e = e, s
+		}
+		return s, e, true
+
+	default:
+		return 0, 0, false
+	}
+}
+
+// NewFeatureIndex creates a new index for the given features. The index
+// refers to the given FeatureSlice, which should not be modified while the
+// index is in use.
+func NewFeatureIndex(ff FeatureSlice) *FeatureIndex {
+	spans := make(map[int]Segment, len(ff))
+	order, others := []int{}, []int{}
+	for i, f := range ff {
+		if s, e, ok := locationSpan(f.Loc); ok {
+			spans[i] = Segment{s, e}
+			order = append(order, i)
+		} else {
+			others = append(others, i)
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return spans[order[i]][0] < spans[order[j]][0]
+	})
+
+	n := len(order)
+	idx := &FeatureIndex{ff, order, make([]int, n), make([]int, n), make([]int, n), others}
+	for i, j := range order {
+		idx.starts[i], idx.ends[i] = spans[j][0], spans[j][1]
+	}
+	idx.build(0, n)
+
+	return idx
+}
+
+// build computes the maximum ending position of the subtree rooted at the
+// middle of the given range, and returns the value.
+func (idx *FeatureIndex) build(lo, hi int) int {
+	if hi <= lo {
+		return -1
+	}
+	mid := (lo + hi) / 2
+	max := idx.ends[mid]
+	max = Max(max, idx.build(lo, mid))
+	max = Max(max, idx.build(mid+1, hi))
+	idx.maxEnds[mid] = max
+	return max
+}
+
+// search calls the given function with the sorted positions of the spans
+// which start before upper and end after lower.
+func (idx *FeatureIndex) search(lo, hi, lower, upper int, f func(i int)) {
+	if hi <= lo {
+		return
+	}
+	mid := (lo + hi) / 2
+	if idx.maxEnds[mid] <= lower {
+		return
+	}
+	idx.search(lo, mid, lower, upper, f)
+	if upper <= idx.starts[mid] {
+		return
+	}
+	if lower < idx.ends[mid] {
+		f(mid)
+	}
+	idx.search(mid+1, hi, lower, upper, f)
+}
+
+// prefixMax returns the maximum ending position of the spans in the subtree
+// rooted at the middle of the given range with sorted positions below k.
+func (idx *FeatureIndex) prefixMax(lo, hi, k int) int {
+	if hi <= lo || k <= lo {
+		return -1
+	}
+	mid := (lo + hi) / 2
+	if hi <= k {
+		return idx.maxEnds[mid]
+	}
+	max := idx.prefixMax(lo, mid, k)
+	if mid < k {
+		max = Max(max, idx.ends[mid])
+		max = Max(max, idx.prefixMax(mid+1, hi, k))
+	}
+	return max
+}
+
+// collect returns the features at the given indices in the order of the
+// original FeatureSlice.
+func (idx *FeatureIndex) collect(indices []int) FeatureSlice {
+	sort.Ints(indices)
+	gg := make(FeatureSlice, len(indices))
+	for i, j := range indices {
+		gg[i] = idx.ff[j]
+	}
+	return gg
+}
+
+// filterOthers appends the indices of the features without a span which
+// satisfy the given filter.
+func (idx *FeatureIndex) filterOthers(indices []int, filter Filter) []int {
+	for _, j := range idx.others {
+		if filter(idx.ff[j]) {
+			indices = append(indices, j)
+		}
+	}
+	return indices
+}
+
+func (idx *FeatureIndex) overlap(lower, upper int) []int {
+	if upper < lower {
+		lower, upper = upper, lower
+	}
+	indices := []int{}
+	idx.search(0, len(idx.order), lower, upper, func(i int) {
+		j := idx.order[i]
+		if LocationOverlap(idx.ff[j].Loc, lower, upper) {
+			indices = append(indices, j)
+		}
+	})
+	return idx.filterOthers(indices, Overlap(lower, upper))
+}
+
+func (idx *FeatureIndex) within(lower, upper int) []int {
+	if upper < lower {
+		lower, upper = upper, lower
+	}
+	indices := []int{}
+	i := sort.SearchInts(idx.starts, lower)
+	for ; i < len(idx.starts) && idx.starts[i] <= upper; i++ {
+		if idx.ends[i] <= upper {
+			indices = append(indices, idx.order[i])
+		}
+	}
+	return idx.filterOthers(indices, Within(lower, upper))
+}
+
+// erase returns the features remaining after erasing the given bounds, which
+// are the source features and the features not within the bounds.
+func (idx *FeatureIndex) erase(lower, upper int) FeatureSlice {
+	erased := make([]bool, len(idx.ff))
+	n := len(idx.ff)
+	for _, j := range idx.within(lower, upper) {
+		if idx.ff[j].Key != "source" {
+			erased[j] = true
+			n--
+		}
+	}
+	gg := make(FeatureSlice, 0, n)
+	for j, f := range idx.ff {
+		if !erased[j] {
+			gg = append(gg, f)
+		}
+	}
+	return gg
+}
+
+// Len returns the number of features in the index.
+func (idx *FeatureIndex) Len() int {
+	return len(idx.ff)
+}
+
+// Overlap returns the features with locations overlapping with the given
+// bounds. The result is identical to filtering the features with the Overlap
+// filter.
+func (idx *FeatureIndex) Overlap(lower, upper int) FeatureSlice {
+	return idx.collect(idx.overlap(lower, upper))
+}
+
+// Within returns the features with locations within the given bounds. The
+// result is identical to filtering the features with the Within filter.
+func (idx *FeatureIndex) Within(lower, upper int) FeatureSlice {
+	return idx.collect(idx.within(lower, upper))
+}
+
+// Nearest returns the features closest to the given bounds, where the
+// distance is measured between the bounds and the span of the location of a
+// feature. Features with spans overlapping with or adjacent to the bounds
+// have a distance of zero. If multiple features are equally close, all of them are returned.
+func (idx *FeatureIndex) Nearest(lower, upper int) FeatureSlice {
+	if upper < lower {
+		lower, upper = upper, lower
+	}
+
+	n := len(idx.order)
+	indices := []int{}
+	add := func(i int) { indices = append(indices, idx.order[i]) }
+
+	idx.search(0, n, lower-1, upper+1, add)
+	if len(indices) > 0 {
+		return idx.collect(indices)
+	}
+
+	// Spans starting at or before upper all end before lower.
+	left := sort.Search(n, func(i int) bool { return upper < idx.starts[i] })
+	end := idx.prefixMax(0, n, left)
+
+	dl, dr := -1, -1
+	if left > 0 {
+		dl = lower - end
+	}
+	if left < n {
+		dr = idx.starts[left] - upper
+	}
+
+	if 0 <= dl && (dr < 0 || dl <= dr) {
+		idx.search(0, n, end-1, upper+1, func(i int) {
+			if idx.ends[i] == end {
+				add(i)
+			}
+		})
+	}
+	if 0 <= dr && (dl < 0 || dr <= dl) {
+		for i := left; i < n && idx.starts[i] == idx.starts[left]; i++ {
+			add(i)
+		}
+	}
+
+	return idx.collect(indices)
+}
+
+type hasFeatureIndex interface {
+	FeatureIndex() *FeatureIndex
+}
+
+// FeatureIndexOf returns the index of the features of the given sequence. If
+// the sequence implements the `FeatureIndex() *FeatureIndex` method, it will
+// be called. Otherwise, a new index will be built.
+func FeatureIndexOf(seq Sequence) *FeatureIndex {
+	if v, ok := seq.(hasFeatureIndex); ok {
+		return v.FeatureIndex()
+	}
+	return NewFeatureIndex(seq.Features())
+}
+
+// IndexedSequence is a sequence which retains the index of its features. The
+// index is used by functions such as Slice and Erase to avoid scanning all of
+// the features of a sequence, which is useful when the same sequence is
+// sliced many times.
+type IndexedSequence struct {
+	Sequence Sequence
+	index    *FeatureIndex
+}
+
+// IndexFeatures returns the given sequence along with the index of its
+// features.
+func IndexFeatures(seq Sequence) IndexedSequence {
+	if v, ok := seq.(IndexedSequence); ok {
+		return v
+	}
+	return IndexedSequence{seq, NewFeatureIndex(seq.Features())}
+}
+
+// Info returns the metadata of the sequence.
+func (seq IndexedSequence) Info() interface{} {
+	return seq.Sequence.Info()
+}
+
+// Features returns the feature table of the sequence.
+func (seq IndexedSequence) Features() FeatureSlice {
+	return seq.Sequence.Features()
+}
+
+// Bytes returns the byte representation of the sequence.
+func (seq IndexedSequence) Bytes() []byte {
+	return seq.Sequence.Bytes()
+}
+
+// Len returns the length of the sequence.
+func (seq IndexedSequence) Len() int {
+	return Len(seq.Sequence)
+}
+
+// FeatureIndex returns the index of the features of the sequence.
+func (seq IndexedSequence) FeatureIndex() *FeatureIndex {
+	return seq.index
+}
+
+// Topology returns the topology of the sequence.
+func (seq IndexedSequence) Topology() Topology {
+	return TopologyOf(seq.Sequence)
+}
+
+// WithInfo creates a shallow copy of the underlying Sequence object and swaps
+// the metadata with the given value.
+func (seq IndexedSequence) WithInfo(info interface{}) Sequence {
+	return WithInfo(seq.Sequence, info)
+}
+
+// WithFeatures creates a shallow copy of the underlying Sequence object and
+// swaps the feature table with the given features.
+func (seq IndexedSequence) WithFeatures(ff []Feature) Sequence {
+	return WithFeatures(seq.Sequence, ff)
+}
+
+// WithBytes creates a shallow copy of the underlying Sequence object and swaps
+// the byte representation with the given byte slice.
+func (seq IndexedSequence) WithBytes(p []byte) Sequence {
+	return WithBytes(seq.Sequence, p)
+}
+
+// WithTopology creates a shallow copy of the underlying Sequence object and
+// swaps the topology value with the given value.
+func (seq IndexedSequence) WithTopology(t Topology) Sequence {
+	return WithTopology(seq.Sequence, t)
+}
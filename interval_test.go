@@ -0,0 +1,137 @@
+package gts
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/go-gts/gts/internal/testutils"
+)
+
+func randomLocation(r *rand.Rand, n int) Location {
+	start := r.Intn(n)
+	var loc Location
+	switch r.Intn(6) {
+	case 0:
+		loc = Point(start)
+	case 1:
+		loc = Between(start)
+	case 2:
+		mid := start + r.Intn(n/10+1)
+		end := mid + 1 + r.Intn(n/10+1)
+		loc = Join(Range(start, mid), Range(end, end+1+r.Intn(n/10+1)))
+	case 3:
+		loc = Ambiguous{start, start + 1 + r.Intn(n/10+1)}
+	default:
+		loc = Range(start, start+1+r.Intn(n/10+1))
+	}
+	if r.Intn(2) == 0 {
+		loc = loc.Complement()
+	}
+	return loc
+}
+
+func randomFeatures(r *rand.Rand, n, length int) FeatureSlice {
+	ff := make(FeatureSlice, n)
+	for i := range ff {
+		ff[i] = NewFeature("misc_feature", randomLocation(r, length), Props{})
+	}
+	ff[0] = NewFeature("source", Range(0, length), Props{})
+	return ff
+}
+
+func nearestFeatures(ff FeatureSlice, lower, upper int) FeatureSlice {
+	dist := func(f Feature) int {
+		s, e, _ := locationSpan(f.Loc)
+		return Max(0, Max(s-upper, lower-e))
+	}
+	min := -1
+	for _, f := range ff {
+		if d := dist(f); min < 0 || d < min {
+			min = d
+		}
+	}
+	return ff.Filter(func(f Feature) bool { return dist(f) == min })
+}
+
+func TestFeatureIndex(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 10, 100} {
+		length := 1000
+		ff := randomFeatures(r, n, length)
+		idx := NewFeatureIndex(ff)
+		testutils.Equals(t, idx.Len(), n)
+
+		for i := 0; i < 100; i++ {
+			lower := r.Intn(length)
+			upper := lower + r.Intn(length/10)
+
+			testutils.Equals(t, idx.Overlap(lower, upper), ff.Filter(Overlap(lower, upper)))
+			testutils.Equals(t, idx.Within(lower, upper), ff.Filter(Within(lower, upper)))
+
+			gg := ff[1:]
+			testutils.Equals(t, NewFeatureIndex(gg).Nearest(lower, upper), nearestFeatures(gg, lower, upper))
+		}
+	}
+}
+
+var featureIndexNearestTests = []struct {
+	lower, upper int
+	out          []int
+}{
+	{0, 5, []int{0}},
+	{10, 20, []int{0, 1}},
+	{12, 14, []int{0}},
+	{16, 18, []int{1}},
+	{21, 22, []int{1}},
+	{22, 24, []int{1, 2}},
+	{26, 28, []int{2}},
+	{40, 50, []int{3}},
+	{50, 40, []int{3}},
+	{34, 34, []int{2, 3}},
+	{100, 100, []int{3}},
+}
+
+func TestFeatureIndexNearest(t *testing.T) {
+	ff := FeatureSlice{
+		NewFeature("gene", Range(0, 10), Props{}),
+		NewFeature("gene", Range(20, 21), Props{}),
+		NewFeature("gene", Join(Range(25, 27), Range(29, 30)), Props{}),
+		NewFeature("gene", Range(38, 45).Complement(), Props{}),
+	}
+	idx := NewFeatureIndex(ff)
+	for _, tt := range featureIndexNearestTests {
+		exp := FeatureSlice{}
+		for _, i := range tt.out {
+			exp = append(exp, ff[i])
+		}
+		testutils.Equals(t, idx.Nearest(tt.lower, tt.upper), exp)
+	}
+
+	testutils.Equals(t, NewFeatureIndex(nil).Nearest(0, 10), FeatureSlice{})
+}
+
+func TestIndexedSequence(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	length := 1000
+	info := "indexed"
+	p := make([]byte, length)
+	for i := range p {
+		p[i] = "acgt"[r.Intn(4)]
+	}
+	seq := New(info, randomFeatures(r, 100, length), p)
+	indexed := IndexFeatures(seq)
+
+	testutils.Equals(t, indexed.Info(), seq.Info())
+	testutils.Equals(t, indexed.Features(), seq.Features())
+	testutils.Equals(t, indexed.Bytes(), seq.Bytes())
+	testutils.Equals(t, Len(indexed), Len(seq))
+	testutils.Equals(t, IndexFeatures(indexed), indexed)
+	testutils.Equals(t, FeatureIndexOf(indexed), indexed.FeatureIndex())
+
+	for i := 0; i < 100; i++ {
+		start := r.Intn(length)
+		end := start + r.Intn(length-start)
+		testutils.Equals(t, Slice(indexed, start, end), Slice(seq, start, end))
+		testutils.Equals(t, Erase(indexed, start, end-start), Erase(seq, start, end-start))
+	}
+}
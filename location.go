@@ -584,6 +584,55 @@ func (ambiguous Ambiguous) Expand(i, n int) Location {
 	return Ambiguous{start, end}
 }
 
+// Remote represents a location in a remote entry, referenced by the accession
+// number and the version of the entry (e.g. `J00194.1:100..202`). A remote
+// location does not occupy any region in the local sequence, and will be left
+// untouched by any operations which manipulate the local coordinates.
+type Remote struct {
+	Accession string
+	Location  Location
+}
+
+// String satisfies the fmt.Stringer interface.
+func (remote Remote) String() string {
+	return fmt.Sprintf("%s:%s", remote.Accession, remote.Location)
+}
+
+// Len returns the total length spanned by the location.
+func (remote Remote) Len() int {
+	return remote.Location.Len()
+}
+
+// Region returns the region pointed to by the location.
+func (remote Remote) Region() Region {
+	return RemoteRegion{remote.Accession, remote.Location.Region()}
+}
+
+// Complement returns the complement location.
+func (remote Remote) Complement() Location {
+	return Complemented{remote}
+}
+
+// Reverse returns the reversed location for the given length sequence.
+func (remote Remote) Reverse(length int) Location {
+	return remote
+}
+
+// Normalize returns a location normalized for the given length sequence.
+func (remote Remote) Normalize(length int) Location {
+	return remote
+}
+
+// Shift the location beyond the given position i by n.
+func (remote Remote) Shift(i, n int) Location {
+	return remote
+}
+
+// Expand the location beyond the given position i by n.
+func (remote Remote) Expand(i, n int) Location {
+	return remote
+}
+
 // LocationList represents a singly linked list of Location objects.
 type LocationList struct {
 	Data Location
@@ -1009,6 +1058,252 @@ func CheckStrand(loc Location) Strand {
 	}
 }
 
+// clipLocation applies the given function to each contiguous location within
+// the given location, retaining the structure of the location. The returned
+// location will be nil if none of the contiguous locations remain.
+func clipLocation(loc Location, f func(loc Location) []Location) Location {
+	switch v := loc.(type) {
+	case nil:
+		return nil
+
+	case Complemented:
+		if u := clipLocation(v.Location, f); u != nil {
+			return Complemented{u}
+		}
+		return nil
+
+	case Joined:
+		locs := clipLocations(v, f)
+		if len(locs) == 0 {
+			return nil
+		}
+		return Join(locs...)
+
+	case Ordered:
+		locs := clipLocations(v, f)
+		if len(locs) == 0 {
+			return nil
+		}
+		return Order(locs...)
+
+	default:
+		locs := f(v)
+		switch len(locs) {
+		case 0:
+			return nil
+		case 1:
+			return locs[0]
+		default:
+			return Join(locs...)
+		}
+	}
+}
+
+func clipLocations(locs []Location, f func(loc Location) []Location) []Location {
+	ret := []Location{}
+	for _, loc := range locs {
+		if u := clipLocation(loc, f); u != nil {
+			ret = append(ret, u)
+		}
+	}
+	return ret
+}
+
+// clipRange returns the range from start to end of the given range. The ends
+// which differ from the original range will be marked as partial.
+func clipRange(ranged Ranged, start, end int) Ranged {
+	partial := ranged.Partial
+	if start != ranged.Start {
+		partial.Partial5 = true
+	}
+	if end != ranged.End {
+		partial.Partial3 = true
+	}
+	return Ranged{start, end, partial}
+}
+
+// Intersect returns the parts of location a which are also covered by location
+// b, or nil if the locations do not overlap. The strand, Joined structure, and
+// Partial flags of location a are retained, and the ends of ranges truncated by
+// the intersection are marked as partial. The strand of location b is ignored.
+// Locations spanning the origin of a circular sequence must be normalized
+// beforehand so that they are represented as Joined locations. Remote locations
+// are not retained.
+func Intersect(a, b Location) Location {
+	if b == nil {
+		return nil
+	}
+	ss := Minimize(b.Region())
+	return clipLocation(a, func(loc Location) []Location {
+		switch v := loc.(type) {
+		case Between:
+			for _, s := range ss {
+				if s[0] <= int(v) && int(v) <= s[1] {
+					return []Location{v}
+				}
+			}
+		case Point:
+			for _, s := range ss {
+				if s[0] <= int(v) && int(v) < s[1] {
+					return []Location{v}
+				}
+			}
+		case Ranged:
+			locs := []Location{}
+			for _, s := range ss {
+				start, end := Max(v.Start, s[0]), Min(v.End, s[1])
+				if start < end {
+					locs = append(locs, clipRange(v, start, end))
+				}
+			}
+			return locs
+		case contiguousLocation:
+			start, end := v.span()
+			for _, s := range ss {
+				if rangeOverlap(start, end, s[0], s[1]) {
+					return []Location{v}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Subtract returns the parts of location a which are not covered by location b,
+// or nil if location a is entirely covered. The strand, Joined structure, and
+// Partial flags of location a are retained, and the ends of ranges truncated by
+// the subtraction are marked as partial. The strand of location b is ignored.
+// Locations spanning the origin of a circular sequence must be normalized
+// beforehand so that they are represented as Joined locations. Remote locations
+// are always retained.
+func Subtract(a, b Location) Location {
+	if b == nil {
+		return a
+	}
+	ss := Minimize(b.Region())
+	return clipLocation(a, func(loc Location) []Location {
+		switch v := loc.(type) {
+		case Between:
+			for _, s := range ss {
+				if s[0] < int(v) && int(v) < s[1] {
+					return nil
+				}
+			}
+		case Point:
+			for _, s := range ss {
+				if s[0] <= int(v) && int(v) < s[1] {
+					return nil
+				}
+			}
+		case Ranged:
+			locs := []Location{}
+			start := v.Start
+			for _, s := range ss {
+				if s[0] == s[1] || s[1] <= start || v.End <= s[0] {
+					continue
+				}
+				if start < s[0] {
+					locs = append(locs, clipRange(v, start, s[0]))
+				}
+				start = Max(start, s[1])
+			}
+			if start < v.End {
+				locs = append(locs, clipRange(v, start, v.End))
+			}
+			return locs
+		case contiguousLocation:
+			start, end := v.span()
+			for _, s := range ss {
+				if rangeWithin(start, end, s[0], s[1]) {
+					return nil
+				}
+			}
+		}
+		return []Location{loc}
+	})
+}
+
+// contiguousLocations returns the contiguous locations in the given location
+// in the order they appear.
+func contiguousLocations(loc Location) []Location {
+	switch v := loc.(type) {
+	case Remote:
+		return nil
+	case Complemented:
+		return contiguousLocations(v.Location)
+	case locationSlice:
+		locs := []Location{}
+		for _, u := range v.slice() {
+			locs = append(locs, contiguousLocations(u)...)
+		}
+		return locs
+	default:
+		return []Location{v}
+	}
+}
+
+// Union returns the location covering both locations a and b. The result will
+// be on the strand of location a, unless location a is nil. The Partial flags
+// of the ranges in either location are retained for the ends which remain in
+// the union. The resulting ranges are ordered by their positions, except that
+// the range containing the first range of location a will come first, so that
+// a Joined location spanning the origin of a circular sequence will continue
+// to do so. Remote locations are not retained, and nil will be returned if
+// neither location has a local part.
+func Union(a, b Location) Location {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+
+	locs := append(contiguousLocations(a), contiguousLocations(b)...)
+	if len(locs) == 0 {
+		return nil
+	}
+	ss := Minimize(Regions{a.Region(), b.Region()})
+
+	partial5, partial3 := make(map[int]bool), make(map[int]bool)
+	points := make(map[int]bool)
+	for _, loc := range locs {
+		switch v := loc.(type) {
+		case Point:
+			points[int(v)] = true
+		case Ranged:
+			partial5[v.Start] = partial5[v.Start] || v.Partial.Partial5
+			partial3[v.End] = partial3[v.End] || v.Partial.Partial3
+		}
+	}
+
+	first, _ := locs[0].(contiguousLocation).span()
+	for i, s := range ss {
+		if s[0] <= first && first <= s[1] {
+			ss = append(ss[i:], ss[:i]...)
+			break
+		}
+	}
+
+	ret := make([]Location, len(ss))
+	for i, s := range ss {
+		switch {
+		case s[0] == s[1]:
+			ret[i] = Between(s[0])
+		case s[0]+1 == s[1] && points[s[0]] && !partial5[s[0]] && !partial3[s[1]]:
+			ret[i] = Point(s[0])
+		default:
+			ret[i] = Ranged{s[0], s[1], Partial{partial5[s[0]], partial3[s[1]]}}
+		}
+	}
+
+	loc := Join(ret...)
+	if CheckStrand(a) == StrandReverse {
+		return loc.Complement()
+	}
+
+	return loc
+}
+
 func parseBetween(state *pars.State, result *pars.Result) error {
 	state.Push()
 	if err := pars.Int(state, result); err != nil {
@@ -1146,6 +1441,51 @@ func parseAmbiguous(state *pars.State, result *pars.Result) error {
 	return nil
 }
 
+func isAccessionByte(c byte) bool {
+	return ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '_' || c == '.'
+}
+
+var parseRemoteLocation = pars.Any(parseRange, parseBetween, parseAmbiguous, parsePoint)
+
+func parseRemote(state *pars.State, result *pars.Result) error {
+	state.Push()
+	accession := []byte{}
+	for {
+		c, err := pars.Next(state)
+		if err != nil || !isAccessionByte(c) {
+			break
+		}
+		if len(accession) == 0 && !(('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z')) {
+			break
+		}
+		accession = append(accession, c)
+		state.Advance()
+	}
+	if len(accession) == 0 {
+		err := pars.NewError("expected an accession", state.Position())
+		state.Pop()
+		return err
+	}
+	c, err := pars.Next(state)
+	if err != nil {
+		state.Pop()
+		return err
+	}
+	if c != ':' {
+		err := pars.NewError("expected `:`", state.Position())
+		state.Pop()
+		return err
+	}
+	state.Advance()
+	if err := parseRemoteLocation(state, result); err != nil {
+		state.Pop()
+		return err
+	}
+	result.SetValue(Remote{string(accession), result.Value.(Location)})
+	state.Drop()
+	return nil
+}
+
 func parseJoin(state *pars.State, result *pars.Result) error {
 	state.Push()
 	if err := state.Request(5); err != nil {
@@ -1259,6 +1599,7 @@ func AsLocation(s string) (Location, error) {
 
 func init() {
 	ParseLocation = pars.Any(
+		parseRemote,
 		parseRange,
 		parseBetween,
 		parseAmbiguous,
@@ -299,6 +299,8 @@ func locRep(loc Location) string {
 		return fmt.Sprintf("Joined(%s)", strings.Join(ss, ", "))
 	case Ambiguous:
 		return fmt.Sprintf("Ambiguous(%d, %d)", v.Start, v.End)
+	case Remote:
+		return fmt.Sprintf("Remote(%s, %s)", v.Accession, locRep(v.Location))
 	case Ordered:
 		ss := make([]string, len(v))
 		for i, u := range v {
@@ -400,6 +402,7 @@ var locationReverseTest = []struct {
 	{Range(0, 3).Complement(), Range(7, 10).Complement()},
 	{Ambiguous{0, 3}, Ambiguous{7, 10}},
 	{Order(Range(0, 3), Range(5, 8)), Order(Range(2, 5), Range(7, 10))},
+	{Remote{"J00194.1", Range(0, 3)}, Remote{"J00194.1", Range(0, 3)}},
 }
 
 func TestLocationReverse(t *testing.T) {
@@ -424,6 +427,7 @@ var locationNormalizeTest = []struct {
 	{Range(10, 13).Complement(), Range(0, 3).Complement()},
 	{Ambiguous{10, 13}, Ambiguous{0, 3}},
 	{Order(Range(10, 13), Range(5, 8)), Order(Range(0, 3), Range(5, 8))},
+	{Remote{"J00194.1", Range(10, 13)}, Remote{"J00194.1", Range(10, 13)}},
 }
 
 func TestLocationNormalize(t *testing.T) {
@@ -602,6 +606,10 @@ var locationShiftTests = []struct {
 	{Ordered{Ranged{3, 6, Complete}, Ranged{13, 16, Complete}}, Ordered{Between(2), Ranged{9, 12, Complete}}, 2, -4},
 	{Ordered{Ranged{3, 6, Complete}, Ranged{13, 16, Complete}}, Ordered{Ranged{3, 6, Complete}, Between(12)}, 12, -4},
 	{Ordered{Ranged{3, 6, Complete}, Ranged{13, 16, Complete}}, Ordered{Between(2), Between(2)}, 2, -14},
+
+	{Remote{"J00194.1", Range(3, 6)}, Remote{"J00194.1", Range(3, 6)}, 2, 4},
+	{Remote{"J00194.1", Range(3, 6)}, Remote{"J00194.1", Range(3, 6)}, 2, -4},
+	{Joined{Ranged{3, 6, Complete}, Remote{"J00194.1", Range(3, 6)}}, Joined{Ranged{7, 10, Complete}, Remote{"J00194.1", Range(3, 6)}}, 2, 4},
 }
 
 func TestLocationShift(t *testing.T) {
@@ -914,6 +922,8 @@ var locationParserPassTests = []struct {
 	{parseJoin, Join(Range(0, 2), Range(3, 5))},
 	{parseAmbiguous, Ambiguous{0, 2}},
 	{parseOrder, Order(Range(0, 2), Range(2, 4))},
+	{parseRemote, Remote{"J00194.1", Range(99, 202)}},
+	{parseRemote, Remote{"J00194", Point(99)}},
 }
 
 var locationParserFailTests = []struct {
@@ -962,6 +972,14 @@ var locationParserFailTests = []struct {
 	{parseAmbiguous, "1"},
 	{parseAmbiguous, "1?"},
 	{parseAmbiguous, "1.?"},
+
+	{parseRemote, ""},
+	{parseRemote, "?"},
+	{parseRemote, "1:1..2"},
+	{parseRemote, "J00194.1"},
+	{parseRemote, "J00194.1:"},
+	{parseRemote, "J00194.1?1..2"},
+	{parseRemote, "J00194.1:join(1..2)"},
 }
 
 func TestLocationParsers(t *testing.T) {
@@ -1008,6 +1026,9 @@ var locationParserTests = []struct {
 	{"1.2", Ambiguous{0, 2}},
 	{"order(1..2,3..4)", Order(Range(0, 2), Range(2, 4))},
 	{"order(1..2, 3..4)", Order(Range(0, 2), Range(2, 4))},
+	{"J00194.1:100..202", Remote{"J00194.1", Range(99, 202)}},
+	{"join(1..100,J00194.1:100..202)", Join(Range(0, 100), Remote{"J00194.1", Range(99, 202)})},
+	{"complement(join(J00194.1:100..202,1..100))", Join(Remote{"J00194.1", Range(99, 202)}, Range(0, 100)).Complement()},
 }
 
 func TestLocationParser(t *testing.T) {
@@ -1049,3 +1070,108 @@ func TestLocationPanics(t *testing.T) {
 	testutils.Panics(t, func() { Join() })
 	testutils.Panics(t, func() { Order() })
 }
+
+var locationIntersectTests = []struct {
+	a, b Location
+	out  Location
+}{
+	{Range(0, 10), nil, nil},
+	{Range(0, 10), Range(20, 30), nil},
+	{Range(0, 10), Range(0, 10), Range(0, 10)},
+	{Range(0, 10), Range(0, 20), Range(0, 10)},
+	{Range(0, 10), Range(5, 20), PartialRange(5, 10, Partial5)},
+	{Range(5, 20), Range(0, 10), PartialRange(5, 10, Partial3)},
+	{Range(0, 10), Range(3, 6), PartialRange(3, 6, PartialBoth)},
+	{PartialRange(0, 10, Partial5), Range(0, 6), PartialRange(0, 6, PartialBoth)},
+	{Range(0, 10), Join(Range(0, 3), Range(6, 10)), Join(PartialRange(0, 3, Partial3), PartialRange(6, 10, Partial5))},
+	{Range(0, 10).Complement(), Range(5, 20), PartialRange(5, 10, Partial5).Complement()},
+	{Range(0, 10), Range(5, 20).Complement(), PartialRange(5, 10, Partial5)},
+	{Join(Range(0, 10), Range(20, 30)), Range(5, 25), Join(PartialRange(5, 10, Partial5), PartialRange(20, 25, Partial3))},
+	{Join(Range(20, 30), Range(0, 10)), Range(5, 25), Join(PartialRange(20, 25, Partial3), PartialRange(5, 10, Partial5))},
+	{Join(Range(0, 10), Range(20, 30)), Range(12, 30), Range(20, 30)},
+	{Join(Range(0, 10).Complement(), Range(20, 30)), Range(5, 25), Join(PartialRange(5, 10, Partial5).Complement(), PartialRange(20, 25, Partial3))},
+	{Order(Range(0, 10), Range(20, 30)), Range(5, 25), Order(PartialRange(5, 10, Partial5), PartialRange(20, 25, Partial3))},
+	{Point(5), Range(0, 10), Point(5)},
+	{Point(5), Range(6, 10), nil},
+	{Between(5), Range(5, 10), Between(5)},
+	{Ambiguous{0, 10}, Range(5, 20), Ambiguous{0, 10}},
+	{Ambiguous{0, 10}, Range(10, 20), nil},
+}
+
+func TestLocationIntersect(t *testing.T) {
+	for _, tt := range locationIntersectTests {
+		out := Intersect(tt.a, tt.b)
+		if !reflect.DeepEqual(out, tt.out) {
+			t.Errorf("Intersect(%v, %v) = %v, want %v", tt.a, tt.b, out, tt.out)
+		}
+	}
+}
+
+var locationSubtractTests = []struct {
+	a, b Location
+	out  Location
+}{
+	{Range(0, 10), nil, Range(0, 10)},
+	{Range(0, 10), Range(20, 30), Range(0, 10)},
+	{Range(0, 10), Range(0, 10), nil},
+	{Range(0, 10), Range(0, 20), nil},
+	{Range(0, 10), Range(5, 20), PartialRange(0, 5, Partial3)},
+	{Range(5, 20), Range(0, 10), PartialRange(10, 20, Partial5)},
+	{Range(0, 10), Range(3, 6), Join(PartialRange(0, 3, Partial3), PartialRange(6, 10, Partial5))},
+	{PartialRange(0, 10, PartialBoth), Range(3, 6), Join(PartialRange(0, 3, PartialBoth), PartialRange(6, 10, PartialBoth))},
+	{Range(0, 10), Between(5), Range(0, 10)},
+	{Range(0, 10).Complement(), Range(3, 6), Join(PartialRange(0, 3, Partial3), PartialRange(6, 10, Partial5)).Complement()},
+	{Join(Range(0, 10), Range(20, 30)), Range(5, 25), Join(PartialRange(0, 5, Partial3), PartialRange(25, 30, Partial5))},
+	{Join(Range(0, 10), Range(20, 30)), Range(0, 10), Range(20, 30)},
+	{Join(Range(90, 100), Range(0, 10)), Range(5, 95), Join(PartialRange(95, 100, Partial5), PartialRange(0, 5, Partial3))},
+	{Order(Range(0, 10), Range(20, 30)), Range(0, 10), Range(20, 30)},
+	{Point(5), Range(0, 10), nil},
+	{Point(5), Range(6, 10), Point(5)},
+	{Between(5), Range(0, 10), nil},
+	{Between(5), Range(5, 10), Between(5)},
+	{Ambiguous{0, 10}, Range(5, 20), Ambiguous{0, 10}},
+	{Ambiguous{0, 10}, Range(0, 20), nil},
+}
+
+func TestLocationSubtract(t *testing.T) {
+	for _, tt := range locationSubtractTests {
+		out := Subtract(tt.a, tt.b)
+		if !reflect.DeepEqual(out, tt.out) {
+			t.Errorf("Subtract(%v, %v) = %v, want %v", tt.a, tt.b, out, tt.out)
+		}
+	}
+}
+
+var locationUnionTests = []struct {
+	a, b Location
+	out  Location
+}{
+	{nil, Range(0, 10), Range(0, 10)},
+	{Range(0, 10), nil, Range(0, 10)},
+	{Range(0, 10), Range(0, 10), Range(0, 10)},
+	{Range(0, 10), Range(5, 20), Range(0, 20)},
+	{Range(0, 10), Range(10, 20), Range(0, 20)},
+	{Range(0, 10), Range(20, 30), Join(Range(0, 10), Range(20, 30))},
+	{Range(20, 30), Range(0, 10), Join(Range(20, 30), Range(0, 10))},
+	{Join(Range(0, 10), Range(30, 40)), Range(15, 20), Join(Range(0, 10), Range(15, 20), Range(30, 40))},
+	{PartialRange(0, 10, Partial5), PartialRange(5, 20, Partial3), PartialRange(0, 20, PartialBoth)},
+	{PartialRange(0, 10, Partial3), Range(5, 20), Range(0, 20)},
+	{Range(0, 10).Complement(), Range(5, 20), Range(0, 20).Complement()},
+	{Range(5, 20), Range(0, 10).Complement(), Range(0, 20)},
+	{Join(Range(90, 100), Range(0, 10)), Range(5, 20), Join(Range(90, 100), Range(0, 20))},
+	{Point(5), Range(10, 20), Join(Point(5), Range(10, 20))},
+	{Point(5), Point(6), Range(5, 7)},
+	{Between(5), Range(10, 20), Join(Between(5), Range(10, 20))},
+	{Between(10), Range(0, 10), Range(0, 10)},
+	{Join(Range(0, 10), Remote{"J00194.1", Range(0, 10)}), Range(5, 20), Range(0, 20)},
+	{Remote{"J00194.1", Range(0, 10)}, Remote{"J00194.1", Range(5, 20)}, nil},
+}
+
+func TestLocationUnion(t *testing.T) {
+	for _, tt := range locationUnionTests {
+		out := Union(tt.a, tt.b)
+		if !reflect.DeepEqual(out, tt.out) {
+			t.Errorf("Union(%v, %v) = %v, want %v", tt.a, tt.b, out, tt.out)
+		}
+	}
+}
@@ -1,7 +1,7 @@
 package gts
 
 import (
-	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/go-pars/pars"
@@ -42,9 +42,17 @@ func locationLocator(loc Location) Locator {
 	}
 }
 
-func filterLocator(f Filter) Locator {
+// filterLocator locates the features satisfying the filter. If the sequence
+// has an index of its features and all of the features satisfying the filter
+// satisfy the query, the candidate features are looked up using the index.
+func filterLocator(f Filter, q *featureQuery) Locator {
 	return func(seq Sequence) Regions {
-		ff := seq.Features()
+		var ff FeatureSlice
+		if v, ok := seq.(hasFeatureIndex); ok && q != nil {
+			ff = q.search(v.FeatureIndex())
+		} else {
+			ff = seq.Features()
+		}
 		ff = ff.Filter(f)
 		rr := make(Regions, len(ff))
 		for i, f := range ff {
@@ -78,12 +86,12 @@ func AsLocator(s string) (Locator, error) {
 			return locationLocator(loc), nil
 		}
 
-		sel, err := Selector(s)
+		sel, query, err := parseSelector(s)
 		if err == nil {
-			return filterLocator(sel), nil
+			return filterLocator(sel, query), nil
 		}
 
-		return nil, errors.New("expected a selector or locator")
+		return nil, fmt.Errorf("expected a selector or locator: %v", err)
 	case 0:
 		mod, err := AsModifier(s[1:])
 		if err != nil {
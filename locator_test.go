@@ -1,8 +1,10 @@
 package gts
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/go-gts/gts/internal/testutils"
 	"github.com/go-test/deep"
 )
 
@@ -15,13 +17,16 @@ var asLocatorTests = []struct {
 	{"3..6", locationLocator(Range(2, 6))},
 	{"complement(3..6)", locationLocator(Range(2, 6).Complement())},
 
-	{"exon", filterLocator(selectorFilter("exon"))},
-	{"exon/gene=INS", filterLocator(selectorFilter("exon"))},
-	{"/gene=INS", filterLocator(selectorFilter("/gene=INS"))},
+	{"exon", filterLocator(selectorFilter("exon"), nil)},
+	{"exon/gene=INS", filterLocator(selectorFilter("exon"), nil)},
+	{"/gene=INS", filterLocator(selectorFilter("/gene=INS"), nil)},
+	{"exon|CDS", filterLocator(Or(Key("exon"), Key("CDS")), nil)},
+	{"(exon && length>100) || CDS", filterLocator(Or(And(Key("exon"), selectorFilter("length>100")), Key("CDS")), nil)},
 
 	{"@^-20..^", resizeLocator(allLocator, HeadHead{-20, 0})},
 	{"@^..$", resizeLocator(allLocator, HeadTail{0, 0})},
-	{"exon@^..$", resizeLocator(filterLocator(selectorFilter("exon")), HeadTail{0, 0})},
+	{"exon@^..$", resizeLocator(filterLocator(selectorFilter("exon"), nil), HeadTail{0, 0})},
+	{"!exon && start<60@^..^+3", resizeLocator(filterLocator(selectorFilter("!exon && start<60"), nil), HeadHead{0, 3})},
 }
 
 var asLocatorFailTests = []string{
@@ -29,6 +34,8 @@ var asLocatorFailTests = []string{
 	"@",
 	"exon/gene=[@",
 	"exon/gene=INS@",
+	"(exon",
+	"exon && length>",
 }
 
 func TestAsLocator(t *testing.T) {
@@ -61,3 +68,46 @@ func TestAsLocator(t *testing.T) {
 		}
 	}
 }
+
+var filterLocatorIndexTests = []string{
+	"overlap=100..200",
+	"within=1..300",
+	"exon && overlap=100..200",
+	"within=50..400 && CDS",
+	"(overlap=1..60 && !exon) && length>10",
+	"overlap=100..200 || CDS",
+	"!within=1..300",
+	"overlap!=100..200",
+}
+
+func TestFilterLocatorIndex(t *testing.T) {
+	seq := New(nil, testFeatureTable, bytes.Repeat([]byte("a"), 500))
+	indexed := IndexFeatures(seq)
+	for _, in := range filterLocatorIndexTests {
+		filter, query, err := parseSelector(in)
+		if err != nil {
+			t.Errorf("parseSelector(%q): %v", in, err)
+			continue
+		}
+		locate := filterLocator(filter, query)
+		if diff := deep.Equal(locate(indexed), locate(seq)); diff != nil {
+			t.Errorf("filterLocator(%q) on indexed sequence: %v", in, diff)
+		}
+	}
+
+	for in, exp := range map[string]*featureQuery{
+		"overlap=100..200":         {false, 99, 200},
+		"exon && within=1..300":    {true, 0, 300},
+		"(overlap=1..60 && !exon)": {false, 0, 60},
+		"overlap=100..200 || CDS":  nil,
+		"!within=1..300":           nil,
+		"overlap!=100..200":        nil,
+	} {
+		_, query, err := parseSelector(in)
+		if err != nil {
+			t.Errorf("parseSelector(%q): %v", in, err)
+			continue
+		}
+		testutils.Equals(t, query, exp)
+	}
+}
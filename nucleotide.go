@@ -31,7 +31,8 @@ func Complement(seq Sequence) Sequence {
 	)
 	ff := make([]Feature, len(seq.Features()))
 	for i, f := range seq.Features() {
-		ff[i] = Feature{f.Key, f.Loc.Complement(), f.Props.Clone()}
+		f = mapFeatureLocations(f, Location.Complement)
+		ff[i] = Feature{f.Key, f.Loc, f.Props.Clone()}
 	}
 	return WithBytes(WithFeatures(seq, ff), p)
 }
@@ -48,8 +49,66 @@ func Transcribe(seq Sequence) Sequence {
 	return WithBytes(seq, p)
 }
 
+// nucleotideClasses maps each ambiguous nucleotide to the nucleotides it
+// will match.
+var nucleotideClasses = map[byte]string{
+	't': "tu",
+	'u': "tu",
+	'r': "agr",
+	'y': "ctuy",
+	'k': "gtuy",
+	'm': "acm",
+	's': "cgs",
+	'w': "atuw",
+	'b': "cgtuyksb",
+	'd': "agturkwd",
+	'h': "actuymwh",
+	'v': "acgrmsv",
+}
+
+func nucleotideMatch(q, c byte) bool {
+	q, c = q|0x20, c|0x20
+	if q == 'n' {
+		return true
+	}
+	if class, ok := nucleotideClasses[q]; ok {
+		return strings.IndexByte(class, c) >= 0
+	}
+	return q == c
+}
+
+func nucleotideEqual(q, c byte) bool {
+	return q|0x20 == c|0x20
+}
+
+// searchBytes returns the byte representation of the sequence to search
+// within. If the sequence is circular, it is extended by the given number of
+// bases from its head so that matches spanning across the origin can be found.
+func searchBytes(seq Sequence, extend int) []byte {
+	p := seq.Bytes()
+	if TopologyOf(seq) != Circular || extend <= 0 {
+		return p
+	}
+	extend = Min(extend, len(p))
+	q := make([]byte, len(p)+extend)
+	copy(q, p)
+	copy(q[len(p):], p[:extend])
+	return q
+}
+
+// withinCircle tests if the segment found in the byte representation returned
+// by searchBytes starts within the original sequence of the given length.
+func withinCircle(s Segment, length int) bool {
+	head, tail := Unpack(s)
+	return head < length && tail-head <= length
+}
+
 // Match for an oligomer within a sequence. The ambiguous nucleotides in the
-// query sequence will match any of the respective nucleotides.
+// query sequence will match any of the respective nucleotides. If the sequence
+// is circular, matches spanning across the origin will also be reported, with
+// the tail of the segment exceeding the length of the sequence. Such a segment
+// can be converted to a location with `Range(head, tail).Normalize(length)`,
+// in the same manner as Rotate.
 func Match(seq Sequence, query Sequence) []Segment {
 	if Len(seq) == 0 || Len(query) == 0 {
 		return nil
@@ -57,45 +116,141 @@ func Match(seq Sequence, query Sequence) []Segment {
 
 	b := strings.Builder{}
 	for _, c := range bytes.ToLower(query.Bytes()) {
-		switch c {
-		case 't', 'u':
-			b.WriteString("[tu]")
-		case 'r':
-			b.WriteString("[agr]")
-		case 'y':
-			b.WriteString("[ctuy]")
-		case 'k':
-			b.WriteString("[gtuy]")
-		case 'm':
-			b.WriteString("[acm]")
-		case 's':
-			b.WriteString("[cgs]")
-		case 'w':
-			b.WriteString("[atuw]")
-		case 'b':
-			b.WriteString("[cgtuyksb]")
-		case 'd':
-			b.WriteString("[agturkwd]")
-		case 'h':
-			b.WriteString("[actuymwh]")
-		case 'v':
-			b.WriteString("[acgrmsv]")
-		case 'n':
+		class, ok := nucleotideClasses[c]
+		switch {
+		case c == 'n':
 			b.WriteString(".")
+		case ok:
+			b.WriteString("[" + class + "]")
 		default:
 			b.WriteByte(c)
 		}
 	}
 
 	s := b.String()
-	p := bytes.ToLower(seq.Bytes())
+	p := bytes.ToLower(searchBytes(seq, Len(query)-1))
 
 	re := regexp.MustCompile(s)
 	pairs := re.FindAllIndex(p, -1)
-	segments := make([]Segment, len(pairs))
-	for i, pair := range pairs {
-		segments[i] = Segment{pair[0], pair[1]}
+	segments := make([]Segment, 0, len(pairs))
+	for _, pair := range pairs {
+		segment := Segment{pair[0], pair[1]}
+		if withinCircle(segment, Len(seq)) {
+			segments = append(segments, segment)
+		}
 	}
 	sort.Sort(BySegment(segments))
 	return segments
 }
+
+// ApproxMatch represents a region of a sequence approximately matching a
+// query, along with the number of differences between the two.
+type ApproxMatch struct {
+	Segment  Segment
+	Distance int
+}
+
+func hammingFind(p, q []byte, k int, eq func(q, c byte) bool) []ApproxMatch {
+	matches := []ApproxMatch{}
+	for i := 0; i+len(q) <= len(p); i++ {
+		d := 0
+		for j := 0; j < len(q) && d <= k; j++ {
+			if !eq(q[j], p[i+j]) {
+				d++
+			}
+		}
+		if d <= k {
+			matches = append(matches, ApproxMatch{Segment{i, i + len(q)}, d})
+		}
+	}
+	return matches
+}
+
+func editFind(p, q []byte, k int, eq func(q, c byte) bool) []ApproxMatch {
+	m := len(q)
+
+	// Each cell holds the edit distance between the query prefix and the best
+	// matching substring of the sequence ending at the current position,
+	// along with the starting position of that substring.
+	dist, head := make([]int, m+1), make([]int, m+1)
+	next, nextHead := make([]int, m+1), make([]int, m+1)
+	for j := range dist {
+		dist[j] = j
+	}
+
+	matches, last := []ApproxMatch{}, -1
+	for i, c := range p {
+		next[0], nextHead[0] = 0, i+1
+		for j := 1; j <= m; j++ {
+			d, h := dist[j-1], head[j-1]
+			if !eq(q[j-1], c) {
+				d++
+			}
+			if next[j-1]+1 < d {
+				d, h = next[j-1]+1, nextHead[j-1]
+			}
+			if dist[j]+1 < d {
+				d, h = dist[j]+1, head[j]
+			}
+			next[j], nextHead[j] = d, h
+		}
+		dist, next = next, dist
+		head, nextHead = nextHead, head
+
+		if dist[m] > k {
+			continue
+		}
+
+		// Keep only the best match out of consecutive matching positions.
+		match := ApproxMatch{Segment{head[m], i + 1}, dist[m]}
+		switch n := len(matches); {
+		case last == i && match.Distance < matches[n-1].Distance:
+			matches[n-1] = match
+		case last != i:
+			matches = append(matches, match)
+		}
+		last = i + 1
+	}
+
+	return matches
+}
+
+func approxFind(seq, query Sequence, k int, indels bool, eq func(q, c byte) bool) []ApproxMatch {
+	if Len(seq) == 0 || Len(query) == 0 {
+		return nil
+	}
+
+	p, q := searchBytes(seq, Len(query)+k-1), query.Bytes()
+
+	var matches []ApproxMatch
+	if indels {
+		matches = editFind(p, q, k, eq)
+	} else {
+		matches = hammingFind(p, q, k, eq)
+	}
+
+	filtered := matches[:0]
+	for _, match := range matches {
+		if withinCircle(match.Segment, Len(seq)) {
+			filtered = append(filtered, match)
+		}
+	}
+	return filtered
+}
+
+// MatchApprox searches for an oligomer within a sequence, allowing at most k
+// differences. The ambiguous nucleotides in the query sequence will match any
+// of the respective nucleotides. If indels is false, only mismatches are
+// counted as differences (Hamming distance). Otherwise, insertions and
+// deletions are also counted (edit distance), and only the first of the best
+// matches is reported out of the matches ending at consecutive positions.
+func MatchApprox(seq, query Sequence, k int, indels bool) []ApproxMatch {
+	return approxFind(seq, query, k, indels, nucleotideMatch)
+}
+
+// SearchApprox searches for a subsequence within a sequence, allowing at most
+// k differences. The differences are counted in the same manner as
+// MatchApprox, but the ambiguous nucleotides are compared literally.
+func SearchApprox(seq, query Sequence, k int, indels bool) []ApproxMatch {
+	return approxFind(seq, query, k, indels, nucleotideEqual)
+}
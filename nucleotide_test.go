@@ -71,3 +71,58 @@ func TestMatch(t *testing.T) {
 		}
 	}
 }
+
+var matchApproxTests = []struct {
+	seq    string
+	query  string
+	k      int
+	indels bool
+	out    []ApproxMatch
+}{
+	{"", "acgt", 1, false, nil},
+	{"acgtacgtac", "", 1, false, nil},
+	{"acgtacgtac", "acgt", 0, false, []ApproxMatch{{Segment{0, 4}, 0}, {Segment{4, 8}, 0}}},
+	{"acgtacgtac", "acct", 0, false, []ApproxMatch{}},
+	{"acgtacgtac", "acct", 1, false, []ApproxMatch{{Segment{0, 4}, 1}, {Segment{4, 8}, 1}}},
+	{"ACGTACGTAC", "ncgw", 0, false, []ApproxMatch{{Segment{0, 4}, 0}, {Segment{4, 8}, 0}}},
+	{"ttacgttt", "acgt", 0, true, []ApproxMatch{{Segment{2, 6}, 0}}},
+	{"ccagtcc", "acgt", 0, true, []ApproxMatch{}},
+	{"ccagtcc", "acgt", 1, true, []ApproxMatch{{Segment{2, 5}, 1}}},
+	{"ccacggtcc", "acgt", 1, true, []ApproxMatch{{Segment{2, 5}, 1}}},
+	{"ccaggtcc", "acgt", 1, true, []ApproxMatch{{Segment{2, 6}, 1}}},
+}
+
+func TestMatchApprox(t *testing.T) {
+	for _, tt := range matchApproxTests {
+		seq := New(nil, nil, []byte(tt.seq))
+		query := New(nil, nil, []byte(tt.query))
+		out := MatchApprox(seq, query, tt.k, tt.indels)
+		testutils.Equals(t, out, tt.out)
+	}
+}
+
+func TestSearchApprox(t *testing.T) {
+	seq := New(nil, nil, []byte("acgtacgtac"))
+	query := New(nil, nil, []byte("ncgw"))
+	testutils.Equals(t, SearchApprox(seq, query, 1, false), []ApproxMatch{})
+	testutils.Equals(t, SearchApprox(seq, query, 2, false), []ApproxMatch{{Segment{0, 4}, 2}, {Segment{4, 8}, 2}})
+	testutils.Equals(t, SearchApprox(seq, query, 1, true), []ApproxMatch{})
+}
+
+func TestMatchCircular(t *testing.T) {
+	p := []byte("ttaccgatac")
+	query := New(nil, nil, []byte("acttmc"))
+
+	linear := newSeqWithTest(Linear, nil, p)
+	testutils.Equals(t, Match(linear, query), []Segment{})
+	testutils.Equals(t, MatchApprox(linear, query, 1, false), []ApproxMatch{})
+
+	circular := newSeqWithTest(Circular, nil, p)
+	testutils.Equals(t, Match(circular, query), []Segment{{8, 14}})
+	testutils.Equals(t, MatchApprox(circular, query, 0, false), []ApproxMatch{{Segment{8, 14}, 0}})
+	testutils.Equals(t, MatchApprox(circular, query, 0, true), []ApproxMatch{{Segment{8, 14}, 0}})
+
+	short := newSeqWithTest(Circular, nil, []byte("acg"))
+	testutils.Equals(t, Match(short, New(nil, nil, []byte("gac"))), []Segment{{2, 5}})
+	testutils.Equals(t, Match(short, New(nil, nil, []byte("acga"))), []Segment{})
+}
@@ -0,0 +1,58 @@
+package gts
+
+// FindORFs returns the locations of the open reading frames on the forward
+// strand of the given sequence. An open reading frame spans from the first
+// start codon following an in-frame stop codon up to and including the next
+// stop codon, using the start and stop codons of the given genetic code.
+// Open reading frames shorter than minLength bases or lacking a stop codon are
+// ignored. If the topology is Circular, open reading frames which span across
+// the origin of the sequence will be reported as Joined locations.
+func FindORFs(seq Sequence, code GeneticCode, top Topology, minLength int) []Location {
+	p := seq.Bytes()
+	n := len(p)
+
+	q, lower, upper := p, 3, n
+	if top == Circular {
+		q = make([]byte, 0, n*2)
+		q = append(q, p...)
+		q = append(q, p...)
+		lower, upper = n+1, n*2
+	}
+
+	locs := []Location{}
+	for end := lower; end <= upper; end++ {
+		if end < 3 || code.Codon(q[end-3:end]) != '*' {
+			continue
+		}
+
+		// Each ORF is bounded by the previous stop codon in the same frame.
+		// For circular sequences, an ORF may not be longer than the sequence.
+		limit := 0
+		if top == Circular {
+			limit = end - n
+		}
+
+		start := -1
+		for i := end - 6; i >= limit; i -= 3 {
+			codon := q[i : i+3]
+			if code.Codon(codon) == '*' {
+				break
+			}
+			if code.IsStart(codon) {
+				start = i
+			}
+		}
+
+		if start < 0 || end-start < minLength {
+			continue
+		}
+
+		var loc Location = Range(start, end)
+		if top == Circular {
+			loc = loc.Normalize(n)
+		}
+		locs = append(locs, loc)
+	}
+
+	return locs
+}
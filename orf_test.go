@@ -0,0 +1,33 @@
+package gts
+
+import (
+	"testing"
+
+	"github.com/go-gts/gts/internal/testutils"
+)
+
+var findORFsTests = []struct {
+	in  string
+	top Topology
+	min int
+	out []Location
+}{
+	{"", Linear, 0, []Location{}},
+	{"", Circular, 0, []Location{}},
+	{"ATGAAATAAGGATGCCCTGA", Linear, 0, []Location{Range(0, 9), Range(11, 20)}},
+	{"ATGAAATAAGGATGCCCTGA", Linear, 9, []Location{Range(0, 9), Range(11, 20)}},
+	{"ATGAAATAAGGATGCCCTGA", Linear, 10, []Location{}},
+	{"ATGATGTAA", Linear, 0, []Location{Range(0, 9)}},
+	{"AAATAGCCCATGCCC", Linear, 0, []Location{}},
+	{"AAATAGCCCATGCCC", Circular, 0, []Location{Join(Range(9, 15), Range(0, 6))}},
+	{"CCCATGCCCTAGAAA", Circular, 0, []Location{Range(3, 12)}},
+}
+
+func TestFindORFs(t *testing.T) {
+	code := GeneticCodes[1]
+	for _, tt := range findORFsTests {
+		seq := New(nil, nil, []byte(tt.in))
+		out := FindORFs(seq, code, tt.top, tt.min)
+		testutils.Equals(t, out, tt.out)
+	}
+}
@@ -0,0 +1,150 @@
+package gts
+
+// MinPrimerBinding is the minimum number of bases at the 3' end of a primer
+// which must bind to the template for the primer to be considered bound.
+const MinPrimerBinding = 15
+
+// Amplicon represents a product of an in-silico PCR. Forward and Reverse are
+// the binding sites of the forward and reverse primers in the template.
+type Amplicon struct {
+	Forward Location
+	Reverse Location
+	Product Sequence
+}
+
+type primerSite struct {
+	head int
+	tail int
+}
+
+func reverseComplementBytes(p []byte) []byte {
+	return Reverse(Complement(New(nil, nil, p))).Bytes()
+}
+
+// findPrimerSites returns the sites in the template where the primer binds
+// along with the number of bases bound. If a primer does not bind in its
+// entirety, the longest 3' portion of the primer which binds is used and the
+// rest is treated as a 5' tail. If reverse is true, the sites where the
+// primer binds to the top strand are reported.
+func findPrimerSites(seq Sequence, primer []byte, mismatches, anchor int, reverse bool) ([]primerSite, int) {
+	p, n := seq.Bytes(), Len(seq)
+
+	for b := len(primer); b > 0 && b >= Min(len(primer), MinPrimerBinding); b-- {
+		q := primer[len(primer)-b:]
+		if reverse {
+			q = reverseComplementBytes(q)
+		}
+
+		sites := []primerSite{}
+		for _, match := range MatchApprox(seq, New(nil, nil, q), mismatches, false) {
+			head, tail := Unpack(match.Segment)
+			ok := true
+			for i := 0; i < Min(anchor, b) && ok; i++ {
+				j := b - 1 - i
+				if reverse {
+					j = i
+				}
+				ok = nucleotideMatch(q[j], p[(head+j)%n])
+			}
+			if ok {
+				sites = append(sites, primerSite{head, tail})
+			}
+		}
+
+		if len(sites) > 0 {
+			return sites, b
+		}
+	}
+
+	return nil, 0
+}
+
+func amplify(seq Sequence, forward, reverse []byte, mismatches, anchor, maxSize int) []Amplicon {
+	n := Len(seq)
+	circular := TopologyOf(seq) == Circular
+
+	fwds, fb := findPrimerSites(seq, forward, mismatches, anchor, false)
+	revs, rb := findPrimerSites(seq, reverse, mismatches, anchor, true)
+
+	amplicons := []Amplicon{}
+	for _, f := range fwds {
+		best, size := -1, 0
+		for i, r := range revs {
+			d := r.tail - f.head
+			if circular {
+				d = wrapPosition(d, n)
+				if d == 0 {
+					d = n
+				}
+			} else if r.head < f.head {
+				continue
+			}
+			if d < fb || d < rb || (maxSize > 0 && d > maxSize) {
+				continue
+			}
+			if best < 0 || d < size {
+				best, size = i, d
+			}
+		}
+
+		if best < 0 {
+			continue
+		}
+
+		r := revs[best]
+
+		var product Sequence
+		switch end := f.head + size; {
+		case size == n:
+			product = Rotate(seq, -f.head)
+		case end > n:
+			product = Slice(seq, f.head, end-n)
+		default:
+			product = Slice(seq, f.head, end)
+		}
+		product = WithTopology(product, Linear)
+
+		ftail := append([]byte{}, forward[:len(forward)-fb]...)
+		head := WithBytes(WithFeatures(product, nil), ftail)
+		tail := New(nil, nil, reverseComplementBytes(reverse[:len(reverse)-rb]))
+		product = Concat(head, product, tail)
+
+		var floc, rloc Location = Range(f.head, f.tail), Range(r.head, r.tail)
+		if circular {
+			floc, rloc = floc.Normalize(n), rloc.Normalize(n)
+		}
+
+		amplicons = append(amplicons, Amplicon{floc, rloc.Complement(), product})
+	}
+
+	return amplicons
+}
+
+// PCR simulates a polymerase chain reaction on the template sequence with the
+// given pair of primers, and returns the amplicons found on the top strand
+// followed by those found on the bottom strand. Each primer may bind with up
+// to the given number of mismatches, but the given number of bases at the 3'
+// end of the primer must match exactly. The 5' portion of a primer that does
+// not bind to the template is treated as a tail and is added to the amplicon.
+// Each forward primer site is paired with the nearest reverse primer site, and
+// products longer than maxSize are ignored unless maxSize is zero. If the
+// template is circular, amplicons spanning across the origin are also
+// reported.
+func PCR(seq, forward, reverse Sequence, mismatches, anchor, maxSize int) []Amplicon {
+	n := Len(seq)
+	if n == 0 || Len(forward) == 0 || Len(reverse) == 0 {
+		return nil
+	}
+
+	fwd, rev := forward.Bytes(), reverse.Bytes()
+	amplicons := amplify(seq, fwd, rev, mismatches, anchor, maxSize)
+
+	cmp := Reverse(Complement(seq))
+	for _, amplicon := range amplify(cmp, fwd, rev, mismatches, anchor, maxSize) {
+		amplicon.Forward = amplicon.Forward.Reverse(n).Complement()
+		amplicon.Reverse = amplicon.Reverse.Reverse(n).Complement()
+		amplicons = append(amplicons, amplicon)
+	}
+
+	return amplicons
+}
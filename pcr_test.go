@@ -0,0 +1,86 @@
+package gts
+
+import (
+	"testing"
+
+	"github.com/go-gts/gts/internal/testutils"
+)
+
+func TestPCR(t *testing.T) {
+	p := []byte("ttacgtacggaaaaaaaaaaccatgctt")
+	fwd := New(nil, nil, []byte("acgtac"))
+	rev := New(nil, nil, []byte("gcatgg"))
+	exp := "acgtacggaaaaaaaaaaccatgc"
+
+	seq := newSeqWithTest(Linear, nil, p)
+	out := PCR(seq, fwd, rev, 0, 0, 0)
+	testutils.Equals(t, len(out), 1)
+	testutils.Equals(t, out[0].Forward, Location(Range(2, 8)))
+	testutils.Equals(t, out[0].Reverse, Range(20, 26).Complement())
+	testutils.Equals(t, string(out[0].Product.Bytes()), exp)
+
+	testutils.Equals(t, len(PCR(seq, fwd, rev, 0, 0, 23)), 0)
+	testutils.Equals(t, len(PCR(seq, fwd, rev, 0, 0, 24)), 1)
+
+	// Swapping the primers yields the product on the bottom strand.
+	out = PCR(seq, rev, fwd, 0, 0, 0)
+	testutils.Equals(t, len(out), 1)
+	testutils.Equals(t, out[0].Forward, Range(20, 26).Complement())
+	testutils.Equals(t, out[0].Reverse, Location(Range(2, 8)))
+	testutils.Equals(t, string(out[0].Product.Bytes()), "gcatggttttttttttccgtacgt")
+
+	// The 3' end of the primer must match exactly.
+	mis := New(nil, nil, []byte("acgaac"))
+	testutils.Equals(t, len(PCR(seq, mis, rev, 0, 0, 0)), 0)
+	testutils.Equals(t, len(PCR(seq, mis, rev, 1, 2, 0)), 1)
+	testutils.Equals(t, len(PCR(seq, mis, rev, 1, 4, 0)), 0)
+
+	// The product is found on the bottom strand.
+	seq = newSeqWithTest(Linear, nil, reverseComplementBytes(p))
+	out = PCR(seq, fwd, rev, 0, 0, 0)
+	testutils.Equals(t, len(out), 1)
+	testutils.Equals(t, out[0].Forward, Range(20, 26).Complement())
+	testutils.Equals(t, out[0].Reverse, Location(Range(2, 8)))
+	testutils.Equals(t, string(out[0].Product.Bytes()), exp)
+
+	// The product spans across the origin.
+	q := append(append([]byte{}, p[10:]...), p[:10]...)
+	seq = newSeqWithTest(Circular, nil, q)
+	out = PCR(seq, fwd, rev, 0, 0, 0)
+	testutils.Equals(t, len(out), 1)
+	testutils.Equals(t, out[0].Forward, Location(Range(20, 26)))
+	testutils.Equals(t, out[0].Reverse, Range(10, 16).Complement())
+	testutils.Equals(t, string(out[0].Product.Bytes()), exp)
+	testutils.Equals(t, TopologyOf(out[0].Product), Linear)
+
+	seq = newSeqWithTest(Linear, nil, q)
+	testutils.Equals(t, len(PCR(seq, fwd, rev, 0, 0, 0)), 0)
+
+	// The reverse primer spans across the origin.
+	q = append(append([]byte{}, p[22:]...), p[:22]...)
+	seq = newSeqWithTest(Circular, nil, q)
+	out = PCR(seq, fwd, rev, 0, 0, 0)
+	testutils.Equals(t, len(out), 1)
+	testutils.Equals(t, out[0].Forward, Location(Range(8, 14)))
+	testutils.Equals(t, out[0].Reverse, Join(Range(26, 28), Range(0, 4)).Complement())
+	testutils.Equals(t, string(out[0].Product.Bytes()), exp)
+
+	testutils.Equals(t, PCR(seq, New(nil, nil, nil), rev, 0, 0, 0), []Amplicon(nil))
+}
+
+func TestPCRTail(t *testing.T) {
+	f, r := "acgtacggatcagtcg", "ccatgcagtgatccag"
+	p := []byte("tt" + f + "aaaaaaaaaa" + r + "tt")
+	seq := newSeqWithTest(Linear, nil, p)
+
+	fwd := New(nil, nil, []byte("gaattc"+f))
+	rev := New(nil, nil, append([]byte("ggatcc"), reverseComplementBytes([]byte(r))...))
+
+	out := PCR(seq, fwd, rev, 0, 0, 0)
+	testutils.Equals(t, len(out), 1)
+	testutils.Equals(t, out[0].Forward, Location(Range(2, 18)))
+	testutils.Equals(t, out[0].Reverse, Range(28, 44).Complement())
+	exp := "gaattc" + f + "aaaaaaaaaa" + r + "ggatcc"
+	testutils.Equals(t, string(out[0].Product.Bytes()), exp)
+	testutils.Equals(t, string(fwd.Bytes()), "gaattc"+f)
+}
@@ -0,0 +1,130 @@
+package gts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-pars/pars"
+)
+
+// locationQualifiers maps the names of the qualifiers which embed a location
+// to the field name of the location. An empty field name indicates that the
+// entire qualifier value is a location.
+var locationQualifiers = map[string]string{
+	"anticodon":      "pos",
+	"transl_except":  "pos",
+	"rpt_unit_range": "",
+	"tag_peptide":    "",
+}
+
+// IsLocationQualifier tests if the values of the given qualifier name embed a
+// location.
+func IsLocationQualifier(name string) bool {
+	_, ok := locationQualifiers[name]
+	return ok
+}
+
+// QualifierLocation represents a qualifier value with an embedded location,
+// such as `(pos:213..215,aa:Trp)` for the `transl_except` qualifier or
+// `202..245` for the `rpt_unit_range` qualifier. The value is split into the
+// location and the text before and after the location.
+type QualifierLocation struct {
+	Prefix string
+	Loc    Location
+	Suffix string
+}
+
+// AsQualifierLocation interprets the value of the given qualifier name as a
+// QualifierLocation.
+func AsQualifierLocation(name, value string) (QualifierLocation, error) {
+	field, ok := locationQualifiers[name]
+	if !ok {
+		return QualifierLocation{}, fmt.Errorf("qualifier %q does not have a location", name)
+	}
+
+	start, end := 0, len(value)
+	if field != "" {
+		open := "(" + field + ":"
+		if !strings.HasPrefix(value, open) {
+			return QualifierLocation{}, fmt.Errorf("expected %q in qualifier value %q", open, value)
+		}
+		start = len(open)
+
+		// The location ends at the first delimiter outside of parentheses.
+		depth := 0
+	loop:
+		for end = start; end < len(value); end++ {
+			switch value[end] {
+			case '(':
+				depth++
+			case ')':
+				if depth == 0 {
+					break loop
+				}
+				depth--
+			case ',':
+				if depth == 0 {
+					break loop
+				}
+			}
+		}
+	}
+
+	s := strings.TrimSpace(value[start:end])
+	result, err := pars.Exact(ParseLocation).Parse(pars.FromString(s))
+	if err != nil {
+		return QualifierLocation{}, fmt.Errorf("invalid location in qualifier value %q: %v", value, err)
+	}
+
+	return QualifierLocation{value[:start], result.Value.(Location), value[end:]}, nil
+}
+
+// String satisfies the fmt.Stringer interface.
+func (q QualifierLocation) String() string {
+	return q.Prefix + q.Loc.String() + q.Suffix
+}
+
+// mapQualifierLocations returns the properties with the locations embedded in
+// the qualifier values transformed by the given function. Any value whose
+// location is lost by the transformation will be removed, and any value which
+// fails to be interpreted will be left untouched. The given properties will
+// be returned as is if it has no location qualifiers.
+func mapQualifierLocations(props Props, f func(loc Location) Location) Props {
+	found := false
+	for _, key := range props.Keys() {
+		found = found || IsLocationQualifier(key)
+	}
+	if !found {
+		return props
+	}
+
+	ret := make(Props, 0, len(props))
+	for _, prop := range props {
+		name, values := prop[0], []string{}
+		for _, value := range prop[1:] {
+			q, err := AsQualifierLocation(name, value)
+			if err != nil {
+				values = append(values, value)
+				continue
+			}
+			loc := f(q.Loc)
+			if loc.Len() == 0 && q.Loc.Len() > 0 {
+				continue
+			}
+			q.Loc = loc
+			values = append(values, q.String())
+		}
+		if len(values) > 0 || len(prop) == 1 {
+			ret = append(ret, append([]string{name}, values...))
+		}
+	}
+
+	return ret
+}
+
+// mapFeatureLocations returns the feature with the feature location and the
+// locations embedded in the qualifier values transformed by the given
+// function.
+func mapFeatureLocations(f Feature, fn func(loc Location) Location) Feature {
+	return Feature{f.Key, fn(f.Loc), mapQualifierLocations(f.Props, fn)}
+}
@@ -0,0 +1,88 @@
+package gts
+
+import (
+	"testing"
+
+	"github.com/go-gts/gts/internal/testutils"
+)
+
+var asQualifierLocationTests = []struct {
+	name, value string
+	out         QualifierLocation
+}{
+	{"transl_except", "(pos:213..215,aa:Trp)", QualifierLocation{"(pos:", Range(212, 215), ",aa:Trp)"}},
+	{"transl_except", "(pos:1017,aa:TERM)", QualifierLocation{"(pos:", Point(1016), ",aa:TERM)"}},
+	{"transl_except", "(pos:X22222:15..17,aa:Ala)", QualifierLocation{"(pos:", Remote{"X22222", Range(14, 17)}, ",aa:Ala)"}},
+	{"anticodon", "(pos:join(5,495..496),aa:Leu,seq:taa)", QualifierLocation{"(pos:", Join(Point(4), Range(494, 496)), ",aa:Leu,seq:taa)"}},
+	{"anticodon", "(pos:complement(4156..4158),aa:Gln,seq:ttg)", QualifierLocation{"(pos:", Range(4155, 4158).Complement(), ",aa:Gln,seq:ttg)"}},
+	{"rpt_unit_range", "202..245", QualifierLocation{"", Range(201, 245), ""}},
+	{"tag_peptide", "90..122", QualifierLocation{"", Range(89, 122), ""}},
+}
+
+var asQualifierLocationFailTests = []struct {
+	name, value string
+}{
+	{"note", "202..245"},
+	{"transl_except", "213..215"},
+	{"transl_except", "(pos:foo,aa:Ala)"},
+	{"rpt_unit_range", "foo"},
+}
+
+func TestAsQualifierLocation(t *testing.T) {
+	for _, tt := range asQualifierLocationTests {
+		out, err := AsQualifierLocation(tt.name, tt.value)
+		if err != nil {
+			t.Errorf("AsQualifierLocation(%q, %q): %v", tt.name, tt.value, err)
+			continue
+		}
+		testutils.Equals(t, out, tt.out)
+		testutils.Equals(t, out.String(), tt.value)
+	}
+
+	for _, tt := range asQualifierLocationFailTests {
+		if _, err := AsQualifierLocation(tt.name, tt.value); err == nil {
+			t.Errorf("expected error in AsQualifierLocation(%q, %q)", tt.name, tt.value)
+		}
+	}
+}
+
+func TestQualifierLocationTransform(t *testing.T) {
+	p := make([]byte, 100)
+	for i := range p {
+		p[i] = 'a'
+	}
+	props := Props{
+		[]string{"gene", "foo"},
+		[]string{"transl_except", "(pos:31..33,aa:Trp)", "(pos:X22222:15..17,aa:Ala)"},
+		[]string{"pseudo"},
+	}
+
+	// Some operations modify the feature table in place.
+	newSeq := func() Sequence {
+		return New(nil, FeatureSlice{NewFeature("CDS", Range(20, 50), props)}, p)
+	}
+
+	transl := func(seq Sequence) []string {
+		return seq.Features()[0].Props.Get("transl_except")
+	}
+
+	testutils.Equals(t, transl(Insert(newSeq(), 10, New(nil, nil, p[:5]))), []string{"(pos:36..38,aa:Trp)", "(pos:X22222:15..17,aa:Ala)"})
+	testutils.Equals(t, transl(Insert(newSeq(), 40, New(nil, nil, p[:5]))), []string{"(pos:31..33,aa:Trp)", "(pos:X22222:15..17,aa:Ala)"})
+	testutils.Equals(t, transl(Embed(newSeq(), 10, New(nil, nil, p[:5]))), []string{"(pos:36..38,aa:Trp)", "(pos:X22222:15..17,aa:Ala)"})
+	testutils.Equals(t, transl(Delete(newSeq(), 10, 5)), []string{"(pos:26..28,aa:Trp)", "(pos:X22222:15..17,aa:Ala)"})
+	testutils.Equals(t, transl(Delete(newSeq(), 29, 4)), []string{"(pos:X22222:15..17,aa:Ala)"})
+	testutils.Equals(t, transl(Slice(newSeq(), 15, 60)), []string{"(pos:16..18,aa:Trp)", "(pos:X22222:15..17,aa:Ala)"})
+	testutils.Equals(t, transl(Slice(newSeq(), 35, 60)), []string{"(pos:X22222:15..17,aa:Ala)"})
+	testutils.Equals(t, transl(Rotate(newSeq(), 10)), []string{"(pos:41..43,aa:Trp)", "(pos:X22222:15..17,aa:Ala)"})
+	testutils.Equals(t, transl(Rotate(newSeq(), 69)), []string{"(pos:join(100..100,1..2),aa:Trp)", "(pos:X22222:15..17,aa:Ala)"})
+	testutils.Equals(t, transl(Reverse(Complement(newSeq()))), []string{"(pos:complement(68..70),aa:Trp)", "(pos:complement(X22222:15..17),aa:Ala)"})
+	testutils.Equals(t, transl(Concat(New(nil, nil, p[:10]), newSeq())), []string{"(pos:41..43,aa:Trp)", "(pos:X22222:15..17,aa:Ala)"})
+
+	// The remaining qualifiers are left intact.
+	out := Rotate(newSeq(), 10).Features()[0].Props
+	testutils.Equals(t, out.Get("gene"), []string{"foo"})
+	testutils.Equals(t, out.Has("pseudo"), true)
+
+	// The original properties are not modified.
+	testutils.Equals(t, props.Get("transl_except"), []string{"(pos:31..33,aa:Trp)", "(pos:X22222:15..17,aa:Ala)"})
+}
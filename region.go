@@ -148,6 +148,9 @@ func (rr Regions) Complement() Region {
 
 // Locate the subsequence corresponding to the region in the given sequence.
 func (rr Regions) Locate(seq Sequence) Sequence {
+	if len(rr) > 1 {
+		seq = IndexFeatures(seq)
+	}
 	seqs := make([]Sequence, len(rr))
 	for i, r := range rr {
 		seqs[i] = r.Locate(seq)
@@ -155,6 +158,52 @@ func (rr Regions) Locate(seq Sequence) Sequence {
 	return Concat(seqs...)
 }
 
+// RemoteRegion represents a region in a remote entry, referenced by the
+// accession number and the version of the entry. The positions of the region
+// are relative to the remote entry.
+type RemoteRegion struct {
+	Accession string
+	Region    Region
+}
+
+// Len returns the length spanned by the region.
+func (r RemoteRegion) Len() int {
+	return r.Region.Len()
+}
+
+// Head returns the 5' boundary of the region.
+func (r RemoteRegion) Head() int {
+	return r.Region.Head()
+}
+
+// Tail returns the 3' boundary of the region.
+func (r RemoteRegion) Tail() int {
+	return r.Region.Tail()
+}
+
+// Resize the region using the given Modifier.
+func (r RemoteRegion) Resize(mod Modifier) Region {
+	return RemoteRegion{r.Accession, r.Region.Resize(mod)}
+}
+
+// Complement returns the equivalent Region on the complement strand.
+func (r RemoteRegion) Complement() Region {
+	return RemoteRegion{r.Accession, r.Region.Complement()}
+}
+
+// Locate the subsequence corresponding to the region in the remote entry. The
+// remote entry is resolved using the Resolver of the given sequence, if any.
+// If the remote entry cannot be resolved, an empty sequence is returned. Use
+// Unresolved to test if the remote entries of a region can be resolved.
+func (r RemoteRegion) Locate(seq Sequence) Sequence {
+	if resolve, ok := resolverOf(seq); ok {
+		if remote, ok := resolve(r.Accession); ok {
+			return r.Region.Locate(remote)
+		}
+	}
+	return New(nil, nil, nil)
+}
+
 // BySegment attaches the methods of sort.Interface to []Segment, sorting in
 // increasing order.
 type BySegment []Segment
@@ -199,6 +248,8 @@ func flattenRegion(arg Region) []Segment {
 			ss = append(ss, flattenRegion(r)...)
 		}
 		return ss
+	case RemoteRegion:
+		return nil
 	default:
 		s := rr.(Segment)
 		if s[1] < s[0] {
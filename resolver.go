@@ -0,0 +1,128 @@
+package gts
+
+// Resolver returns the sequence of the entry with the given accession number
+// and version, which is used to locate the regions of remote locations. The
+// boolean value will be false if the entry could not be resolved.
+type Resolver func(accession string) (Sequence, bool)
+
+type hasResolver interface {
+	Resolver() Resolver
+}
+
+// resolverOf returns the Resolver of the given sequence if available.
+func resolverOf(seq Sequence) (Resolver, bool) {
+	switch v := seq.(type) {
+	case hasResolver:
+		return v.Resolver(), true
+	case IndexedSequence:
+		return resolverOf(v.Sequence)
+	default:
+		return nil, false
+	}
+}
+
+// Unresolved returns the accession numbers of the remote entries referenced by
+// the region which cannot be resolved using the Resolver of the given sequence.
+// The regions of such entries will be located as empty sequences.
+func Unresolved(seq Sequence, r Region) []string {
+	resolve, ok := resolverOf(seq)
+	seen := make(map[string]bool)
+	ret := []string{}
+	for _, accession := range remoteAccessions(r) {
+		if seen[accession] {
+			continue
+		}
+		seen[accession] = true
+		if ok {
+			if _, found := resolve(accession); found {
+				continue
+			}
+		}
+		ret = append(ret, accession)
+	}
+	return ret
+}
+
+// remoteAccessions returns the accession numbers of the remote entries
+// referenced by the region.
+func remoteAccessions(r Region) []string {
+	switch v := r.(type) {
+	case RemoteRegion:
+		return []string{v.Accession}
+	case Regions:
+		ret := []string{}
+		for _, r := range v {
+			ret = append(ret, remoteAccessions(r)...)
+		}
+		return ret
+	default:
+		return nil
+	}
+}
+
+// ResolvedSequence is a sequence which retains a Resolver to locate the
+// regions of the remote locations in its features.
+type ResolvedSequence struct {
+	Sequence Sequence
+	resolve  Resolver
+}
+
+// WithResolver returns the given sequence along with the Resolver for
+// locating the regions of remote locations.
+func WithResolver(seq Sequence, resolve Resolver) ResolvedSequence {
+	return ResolvedSequence{seq, resolve}
+}
+
+// Info returns the metadata of the sequence.
+func (seq ResolvedSequence) Info() interface{} {
+	return seq.Sequence.Info()
+}
+
+// Features returns the feature table of the sequence.
+func (seq ResolvedSequence) Features() FeatureSlice {
+	return seq.Sequence.Features()
+}
+
+// Bytes returns the byte representation of the sequence.
+func (seq ResolvedSequence) Bytes() []byte {
+	return seq.Sequence.Bytes()
+}
+
+// Len returns the length of the sequence.
+func (seq ResolvedSequence) Len() int {
+	return Len(seq.Sequence)
+}
+
+// Resolver returns the Resolver of the sequence.
+func (seq ResolvedSequence) Resolver() Resolver {
+	return seq.resolve
+}
+
+// Topology returns the topology of the sequence.
+func (seq ResolvedSequence) Topology() Topology {
+	return TopologyOf(seq.Sequence)
+}
+
+// WithInfo creates a shallow copy of the underlying Sequence object and swaps
+// the metadata with the given value. The Resolver is retained.
+func (seq ResolvedSequence) WithInfo(info interface{}) Sequence {
+	return ResolvedSequence{WithInfo(seq.Sequence, info), seq.resolve}
+}
+
+// WithFeatures creates a shallow copy of the underlying Sequence object and
+// swaps the feature table with the given features. The Resolver is retained.
+func (seq ResolvedSequence) WithFeatures(ff []Feature) Sequence {
+	return ResolvedSequence{WithFeatures(seq.Sequence, ff), seq.resolve}
+}
+
+// WithBytes creates a shallow copy of the underlying Sequence object and swaps
+// the byte representation with the given byte slice. The Resolver is retained.
+func (seq ResolvedSequence) WithBytes(p []byte) Sequence {
+	return ResolvedSequence{WithBytes(seq.Sequence, p), seq.resolve}
+}
+
+// WithTopology creates a shallow copy of the underlying Sequence object and
+// swaps the topology value with the given value. The Resolver is retained.
+func (seq ResolvedSequence) WithTopology(t Topology) Sequence {
+	return ResolvedSequence{WithTopology(seq.Sequence, t), seq.resolve}
+}
@@ -0,0 +1,62 @@
+package gts
+
+import (
+	"testing"
+
+	"github.com/go-gts/gts/internal/testutils"
+)
+
+func TestResolvedSequence(t *testing.T) {
+	info := "local"
+	ff := FeatureSlice{NewFeature("source", Range(0, 8), nil)}
+	seq := New(info, ff, []byte("atgcatgc"))
+	remote := New(nil, nil, []byte("ggggcccc"))
+
+	resolve := func(accession string) (Sequence, bool) {
+		if accession == "J00194.1" {
+			return remote, true
+		}
+		return nil, false
+	}
+	resolved := WithResolver(seq, resolve)
+
+	testutils.Equals(t, resolved.Info(), seq.Info())
+	testutils.Equals(t, resolved.Features(), seq.Features())
+	testutils.Equals(t, resolved.Bytes(), seq.Bytes())
+	testutils.Equals(t, Len(resolved), Len(seq))
+	testutils.Equals(t, TopologyOf(resolved), TopologyOf(seq))
+
+	loc := Join(Range(0, 2), Remote{"J00194.1", Range(2, 6)})
+	testutils.Equals(t, loc.Region().Locate(resolved).Bytes(), []byte("atggcc"))
+	testutils.Equals(t, loc.Region().Locate(IndexFeatures(resolved)).Bytes(), []byte("atggcc"))
+	testutils.Equals(t, loc.Complement().Region().Locate(resolved).Bytes(), []byte("ggccat"))
+
+	// Remote locations cannot be resolved without a Resolver.
+	testutils.Equals(t, loc.Region().Locate(seq).Bytes(), []byte("at"))
+
+	// Unknown entries cannot be resolved.
+	unknown := Remote{"X22222", Range(2, 6)}
+	testutils.Equals(t, Len(unknown.Region().Locate(resolved)), 0)
+
+	both := Join(Range(0, 2), Remote{"J00194.1", Range(2, 6)}, unknown, unknown)
+	testutils.Equals(t, Unresolved(resolved, both.Region()), []string{"X22222"})
+	testutils.Equals(t, Unresolved(IndexFeatures(resolved), both.Complement().Region()), []string{"X22222"})
+	testutils.Equals(t, Unresolved(seq, both.Region()), []string{"J00194.1", "X22222"})
+	testutils.Equals(t, Unresolved(seq, Range(0, 2).Region()), []string{})
+
+	// The Resolver is retained through the transformations.
+	transformed := []Sequence{
+		WithInfo(resolved, "other"),
+		WithFeatures(resolved, nil),
+		WithBytes(resolved, []byte("ccccgggg")),
+		WithTopology(resolved, Circular),
+		Slice(resolved, 0, 4),
+	}
+	for _, out := range transformed {
+		if _, ok := out.(ResolvedSequence); !ok {
+			t.Errorf("%T does not retain the Resolver", out)
+		}
+		testutils.Equals(t, Unresolved(out, loc.Region()), []string{})
+		testutils.Equals(t, loc.Region().Locate(out).Bytes()[2:], []byte("ggcc"))
+	}
+}
@@ -0,0 +1,386 @@
+package gts
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+func shiftSelector(s string) (string, string) {
+	esc, quote := false, false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case esc:
+			esc = false
+		case s[i] == '\\':
+			esc = true
+		case s[i] == '"':
+			quote = !quote
+		case s[i] == '/' && !quote:
+			return s[:i], s[i+1:]
+		}
+	}
+	return s, ""
+}
+
+func unquoteSelector(s string) string {
+	if len(s) > 1 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+func toQualifier(s string) (Filter, error) {
+	switch i := strings.IndexByte(s, '='); i {
+	case -1:
+		return Qualifier(s, "")
+	default:
+		return Qualifier(s[:i], unquoteSelector(s[i+1:]))
+	}
+}
+
+func toKeys(s string) Filter {
+	keys := strings.Split(s, "|")
+	if len(keys) == 1 {
+		return Key(s)
+	}
+	filters := make([]Filter, len(keys))
+	for i, key := range keys {
+		filters[i] = Key(key)
+	}
+	return Or(filters...)
+}
+
+var predicateRegexp = regexp.MustCompile(`^([a-z]+)\s*(==|!=|<=|>=|=|<|>)\s*(.*)$`)
+
+func compareOperator(op string) func(a, b int) bool {
+	switch op {
+	case "!=":
+		return func(a, b int) bool { return a != b }
+	case "<":
+		return func(a, b int) bool { return a < b }
+	case "<=":
+		return func(a, b int) bool { return a <= b }
+	case ">":
+		return func(a, b int) bool { return a > b }
+	case ">=":
+		return func(a, b int) bool { return a >= b }
+	default:
+		return func(a, b int) bool { return a == b }
+	}
+}
+
+func parseSelectorRange(s string) (int, int, error) {
+	i := strings.Index(s, "..")
+	if i < 0 {
+		return 0, 0, fmt.Errorf("expected a range of the form `start..end`, got %q", s)
+	}
+	start, err := strconv.Atoi(strings.TrimSpace(s[:i]))
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := strconv.Atoi(strings.TrimSpace(s[i+2:]))
+	if err != nil {
+		return 0, 0, err
+	}
+	return start - 1, end, nil
+}
+
+// toPredicate converts a predicate of the form `name op value` to a Filter.
+// The positions are one-based and inclusive, as in a feature table.
+func toPredicate(s string) (Filter, error) {
+	match := predicateRegexp.FindStringSubmatch(s)
+	if match == nil {
+		return FalseFilter, fmt.Errorf("invalid selector predicate: %q", s)
+	}
+	name, op, value := match[1], match[2], strings.TrimSpace(match[3])
+
+	negate := func(filter Filter) (Filter, error) {
+		switch op {
+		case "=", "==":
+			return filter, nil
+		case "!=":
+			return Not(filter), nil
+		default:
+			return FalseFilter, fmt.Errorf("operator %q cannot be used with %q", op, name)
+		}
+	}
+
+	switch name {
+	case "strand":
+		switch value {
+		case "forward", "+":
+			return negate(ForwardStrand)
+		case "reverse", "-":
+			return negate(ReverseStrand)
+		default:
+			return FalseFilter, fmt.Errorf("unknown strand: %q", value)
+		}
+
+	case "within", "overlap":
+		lower, upper, err := parseSelectorRange(value)
+		if err != nil {
+			return FalseFilter, err
+		}
+		if name == "within" {
+			return negate(Within(lower, upper))
+		}
+		return negate(Overlap(lower, upper))
+
+	case "length", "start", "end":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return FalseFilter, err
+		}
+		cmp := compareOperator(op)
+		return func(f Feature) bool {
+			if name == "length" {
+				return cmp(f.Loc.Len(), n)
+			}
+			start, end, ok := locationSpan(f.Loc)
+			if !ok {
+				return false
+			}
+			if name == "start" {
+				return cmp(start+1, n)
+			}
+			return cmp(end, n)
+		}, nil
+
+	default:
+		return FalseFilter, fmt.Errorf("unknown selector predicate: %q", name)
+	}
+}
+
+// featureQuery represents the bounds of a `within` or `overlap` predicate
+// which all of the selected features satisfy, so that the candidate features
+// can be looked up using a FeatureIndex.
+type featureQuery struct {
+	within       bool
+	lower, upper int
+}
+
+// toQuery returns the featureQuery of a predicate if the predicate requires
+// the features to be within or overlap with a range.
+func toQuery(s string) *featureQuery {
+	match := predicateRegexp.FindStringSubmatch(s)
+	if match == nil || (match[2] != "=" && match[2] != "==") {
+		return nil
+	}
+	name := match[1]
+	if name != "within" && name != "overlap" {
+		return nil
+	}
+	lower, upper, err := parseSelectorRange(strings.TrimSpace(match[3]))
+	if err != nil {
+		return nil
+	}
+	return &featureQuery{name == "within", lower, upper}
+}
+
+// search returns the features in the index satisfying the query.
+func (q featureQuery) search(idx *FeatureIndex) FeatureSlice {
+	if q.within {
+		return idx.Within(q.lower, q.upper)
+	}
+	return idx.Overlap(q.lower, q.upper)
+}
+
+// toTerm converts a single term of a selector expression to a Filter.
+func toTerm(s string) (Filter, *featureQuery, error) {
+	head, tail := shiftSelector(s)
+	if strings.ContainsAny(head, "=<>!") {
+		filter, err := toPredicate(s)
+		return filter, toQuery(s), err
+	}
+
+	filter := toKeys(head)
+	for tail != "" {
+		head, tail = shiftSelector(tail)
+		props, err := toQualifier(head)
+		if err != nil {
+			return FalseFilter, nil, err
+		}
+		filter = And(filter, props)
+	}
+	return filter, nil, nil
+}
+
+type selectorParser struct {
+	s string
+	i int
+}
+
+func (p *selectorParser) skip() {
+	for p.i < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.i]) >= 0 {
+		p.i++
+	}
+}
+
+func (p *selectorParser) consume(tok string) bool {
+	p.skip()
+	if strings.HasPrefix(p.s[p.i:], tok) {
+		p.i += len(tok)
+		return true
+	}
+	return false
+}
+
+// parseOr parses the expression into a Filter. Along with the Filter, the
+// parsing functions return the featureQuery satisfied by all of the selected
+// features if there is one.
+func (p *selectorParser) parseOr() (Filter, *featureQuery, error) {
+	filter, query, err := p.parseAnd()
+	if err != nil {
+		return FalseFilter, nil, err
+	}
+	filters := []Filter{filter}
+	for p.consume("||") {
+		filter, _, err := p.parseAnd()
+		if err != nil {
+			return FalseFilter, nil, err
+		}
+		filters = append(filters, filter)
+	}
+	if len(filters) == 1 {
+		return filter, query, nil
+	}
+	return Or(filters...), nil, nil
+}
+
+func (p *selectorParser) parseAnd() (Filter, *featureQuery, error) {
+	filter, query, err := p.parseUnary()
+	if err != nil {
+		return FalseFilter, nil, err
+	}
+	filters := []Filter{filter}
+	for p.consume("&&") {
+		filter, q, err := p.parseUnary()
+		if err != nil {
+			return FalseFilter, nil, err
+		}
+		if query == nil {
+			query = q
+		}
+		filters = append(filters, filter)
+	}
+	if len(filters) == 1 {
+		return filter, query, nil
+	}
+	return And(filters...), query, nil
+}
+
+func (p *selectorParser) parseUnary() (Filter, *featureQuery, error) {
+	switch {
+	case p.consume("!"):
+		filter, _, err := p.parseUnary()
+		if err != nil {
+			return FalseFilter, nil, err
+		}
+		return Not(filter), nil, nil
+
+	case p.consume("("):
+		filter, query, err := p.parseOr()
+		if err != nil {
+			return FalseFilter, nil, err
+		}
+		if !p.consume(")") {
+			return FalseFilter, nil, fmt.Errorf("expected `)` at position %d", p.i+1)
+		}
+		return filter, query, nil
+
+	default:
+		return p.parseTerm()
+	}
+}
+
+// parseTerm reads a term up to the next operator or unbalanced parenthesis.
+// Operators within balanced parentheses, quotes, or escaped are considered
+// to be part of the term.
+func (p *selectorParser) parseTerm() (Filter, *featureQuery, error) {
+	start, depth, quote := p.i, 0, false
+
+	i := p.i
+loop:
+	for ; i < len(p.s); i++ {
+		c := p.s[i]
+		switch {
+		case c == '\\':
+			i++
+		case c == '"':
+			quote = !quote
+		case quote:
+			// Skip the contents of quotes.
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth == 0 {
+				break loop
+			}
+			depth--
+		case c == '&' || c == '|':
+			if depth == 0 && strings.HasPrefix(p.s[i:], strings.Repeat(string(c), 2)) {
+				break loop
+			}
+		}
+	}
+
+	if quote {
+		return FalseFilter, nil, errors.New("unterminated quote in selector")
+	}
+
+	p.i = Min(i, len(p.s))
+	term := strings.TrimSpace(p.s[start:p.i])
+	if term == "" {
+		return FalseFilter, nil, fmt.Errorf("expected a selector at position %d", start+1)
+	}
+	return toTerm(term)
+}
+
+// Selector generates a new Filter which will return true if a given Feature
+// satisfies the criteria specified by the selection string. A selector in GTS
+// is defined as follows:
+//
+//	[feature_key[|feature_key]...]/qualifier_name=regexp[/qualifier_name=regexp]...
+//
+// If the qualifier name is omitted, the values for every qualifier name will
+// be tested. A regular expression may be enclosed in double quotes to retain
+// leading or trailing whitespaces and slashes.
+//
+// In place of a selector, a predicate of the form `name op value` may be
+// given. The predicates `length`, `start`, and `end` compare the length and
+// the one-based positions of the feature location with an integer using one
+// of the operators `=`, `!=`, `<`, `<=`, `>`, or `>=`. The predicate `strand`
+// tests the strand of the feature location against `forward` or `reverse`,
+// and the predicates `within` and `overlap` test the feature location against
+// a range of the form `start..end`.
+//
+// Selectors and predicates may be combined using the `!`, `&&`, and `||`
+// operators and grouped using parentheses, where `!` takes precedence over
+// `&&` and `&&` takes precedence over `||`.
+func Selector(sel string) (Filter, error) {
+	filter, _, err := parseSelector(sel)
+	return filter, err
+}
+
+// parseSelector parses the selector string as in Selector, along with the
+// featureQuery satisfied by the selected features if there is one.
+func parseSelector(sel string) (Filter, *featureQuery, error) {
+	if strings.TrimSpace(sel) == "" {
+		return TrueFilter, nil, nil
+	}
+
+	p := &selectorParser{sel, 0}
+	filter, query, err := p.parseOr()
+	if err != nil {
+		return FalseFilter, nil, err
+	}
+
+	if p.skip(); p.i < len(p.s) {
+		return FalseFilter, nil, fmt.Errorf("unexpected %q in selector", p.s[p.i:])
+	}
+
+	return filter, query, nil
+}
@@ -0,0 +1,94 @@
+package gts
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+var selectorTestTable = FeatureSlice{
+	NewFeature("source", Range(0, 5386), Props{[]string{"mol_type", "Genomic DNA"}}),
+	NewFeature("gene", Range(50, 100), Props{[]string{"gene", "A"}, []string{"note", "a/b"}}),
+	NewFeature("CDS", Range(50, 100), Props{[]string{"gene", "A"}, []string{"product", "DNA polymerase"}}),
+	NewFeature("gene", Range(200, 500).Complement(), Props{[]string{"gene", "B"}}),
+	NewFeature("CDS", Range(200, 500).Complement(), Props{[]string{"gene", "B"}, []string{"product", "(putative) helicase"}}),
+	NewFeature("tRNA", Join(Range(600, 620), Range(640, 660)), Props{[]string{"product", "tRNA-Gly"}}),
+}
+
+var selectorTests = []struct {
+	in  string
+	out []int
+}{
+	{"", []int{0, 1, 2, 3, 4, 5}},
+	{"CDS", []int{2, 4}},
+	{"CDS|tRNA", []int{2, 4, 5}},
+	{"CDS/gene=A", []int{2}},
+	{"/gene=B", []int{3, 4}},
+	{"gene/note=a\\/b", []int{1}},
+	{`gene/note="a/b"`, []int{1}},
+	{"CDS/product=DNA polymerase", []int{2}},
+	{"CDS/product=(putative)", []int{4}},
+	{"CDS/product=X|helicase", []int{4}},
+	{"!CDS", []int{0, 1, 3, 5}},
+	{"! ! CDS", []int{2, 4}},
+	{"CDS || tRNA", []int{2, 4, 5}},
+	{"CDS && /gene=A", []int{2}},
+	{"gene || CDS && /gene=A", []int{1, 2, 3}},
+	{"(gene || CDS) && /gene=A", []int{1, 2}},
+	{"!(gene || CDS)", []int{0, 5}},
+	{"(CDS/product=(DNA|RNA))", []int{2}},
+	{"strand=forward", []int{0, 1, 2, 5}},
+	{"strand=-", []int{3, 4}},
+	{"strand != reverse && !source", []int{1, 2, 5}},
+	{"length>50", []int{0, 3, 4}},
+	{"length<=50", []int{1, 2, 5}},
+	{"length==40", []int{5}},
+	{"start=601", []int{5}},
+	{"end<100", []int{}},
+	{"end<=100 && !source", []int{1, 2}},
+	{"within=1..100", []int{1, 2}},
+	{"overlap=450..610", []int{0, 3, 4, 5}},
+	{"overlap!=450..610", []int{1, 2}},
+	{"CDS && (length>100 || strand=forward)", []int{2, 4}},
+}
+
+var selectorErrorTests = []string{
+	"/gene=[",
+	"(CDS",
+	"CDS)",
+	"CDS &&",
+	"|| CDS",
+	"()",
+	`/gene="A`,
+	"strand=both",
+	"strand<forward",
+	"length>long",
+	"within=100",
+	"within=a..b",
+	"foo=bar",
+	"gene=/gene=A",
+}
+
+func TestSelector(t *testing.T) {
+	for _, tt := range selectorTests {
+		filter, err := Selector(tt.in)
+		if err != nil {
+			t.Errorf("Selector(%q): %v", tt.in, err)
+			continue
+		}
+		exp := FeatureSlice{}
+		for _, i := range tt.out {
+			exp = append(exp, selectorTestTable[i])
+		}
+		out := selectorTestTable.Filter(filter)
+		if diff := deep.Equal(out, exp); diff != nil {
+			t.Errorf("Selector(%q): %v", tt.in, diff)
+		}
+	}
+
+	for _, in := range selectorErrorTests {
+		if _, err := Selector(in); err == nil {
+			t.Errorf("expected error in Selector(%q)", in)
+		}
+	}
+}
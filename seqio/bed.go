@@ -0,0 +1,266 @@
+package seqio
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-gts/gts"
+	"github.com/go-pars/pars"
+)
+
+// BEDWriter writes the features of a gts.Sequence to an io.Writer in BED12
+// format. Each feature is written as a single line, with the segments of
+// joined locations represented as blocks. The sequence itself is not written.
+type BEDWriter struct {
+	w io.Writer
+}
+
+// bedSegments returns the segments of the location in the order they are read
+// on the strand of the location.
+func bedSegments(loc gts.Location) []gts.Segment {
+	switch v := loc.(type) {
+	case gts.Complemented:
+		ss := bedSegments(v.Location)
+		for i, j := 0, len(ss)-1; i < j; i, j = i+1, j-1 {
+			ss[i], ss[j] = ss[j], ss[i]
+		}
+		return ss
+	case gts.Joined:
+		return bedSegmentsList(v)
+	case gts.Ordered:
+		return bedSegmentsList(v)
+	case gts.Remote:
+		return nil
+	default:
+		return gts.Minimize(loc.Region())
+	}
+}
+
+func bedSegmentsList(locs []gts.Location) []gts.Segment {
+	ss := []gts.Segment{}
+	for _, loc := range locs {
+		ss = append(ss, bedSegments(loc)...)
+	}
+	return ss
+}
+
+// bedBlocks returns the blocks of the location in the order on the forward
+// strand. The blocks are minimized unless the segments are out of order, as
+// in a location spanning the origin of a circular sequence, in which case the
+// original order is retained so that the location can be restored.
+func bedBlocks(loc gts.Location) []gts.Segment {
+	ss := bedSegments(loc)
+	if gts.CheckStrand(loc) == gts.StrandReverse {
+		for i, j := 0, len(ss)-1; i < j; i, j = i+1, j-1 {
+			ss[i], ss[j] = ss[j], ss[i]
+		}
+	}
+	for i := 1; i < len(ss); i++ {
+		if ss[i][0] < ss[i-1][0] {
+			return ss
+		}
+	}
+	return gts.Minimize(loc.Region())
+}
+
+// formatBEDLine formats the feature as a BED line. A feature without any
+// region to represent, such as an empty join or a remote location, is written
+// as a comment line. The remote parts of a location are omitted from the BED
+// line and noted in a preceding comment line.
+func formatBEDLine(chrom string, f gts.Feature) string {
+	remote := len(remoteAccessions(f.Loc)) > 0
+	blocks := bedBlocks(f.Loc)
+	if len(blocks) == 0 {
+		if remote {
+			return formatRemoteNote(chrom, f, true)
+		}
+		return fmt.Sprintf("# skipped %s %s in %s: no region to represent\n", f.Key, f.Loc, chrom)
+	}
+
+	note := ""
+	if remote {
+		note = formatRemoteNote(chrom, f, false)
+	}
+	start, end := blocks[0][0], blocks[0][1]
+	for _, block := range blocks[1:] {
+		start, end = gts.Min(start, block[0]), gts.Max(end, block[1])
+	}
+
+	strand := "."
+	switch gts.CheckStrand(f.Loc) {
+	case gts.StrandForward:
+		strand = "+"
+	case gts.StrandReverse:
+		strand = "-"
+	}
+
+	thickStart, thickEnd := start, start
+	if f.Key == "CDS" {
+		thickEnd = end
+	}
+
+	sizes := make([]string, len(blocks))
+	starts := make([]string, len(blocks))
+	for i, block := range blocks {
+		sizes[i] = strconv.Itoa(block.Len())
+		starts[i] = strconv.Itoa(block[0] - start)
+	}
+
+	return note + fmt.Sprintf(
+		"%s\t%d\t%d\t%s\t0\t%s\t%d\t%d\t0\t%d\t%s,\t%s,\n",
+		chrom, start, end, f.Key, strand, thickStart, thickEnd,
+		len(blocks), strings.Join(sizes, ","), strings.Join(starts, ","),
+	)
+}
+
+// WriteSeq satisfies the seqio.SeqWriter interface.
+func (w BEDWriter) WriteSeq(seq gts.Sequence) (int, error) {
+	chrom := SequenceID(seq)
+	if chrom == "" {
+		chrom = "."
+	}
+
+	b := strings.Builder{}
+	for _, f := range seq.Features() {
+		if f.Key != "source" {
+			b.WriteString(formatBEDLine(chrom, f))
+		}
+	}
+
+	return io.WriteString(w.w, b.String())
+}
+
+func parseBEDInts(s string, n int) ([]int, error) {
+	fields := strings.Split(strings.TrimSuffix(s, ","), ",")
+	if len(fields) != n {
+		return nil, fmt.Errorf("expected %d values, got %d", n, len(fields))
+	}
+	ints := make([]int, n)
+	for i, field := range fields {
+		v, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+		ints[i] = v
+	}
+	return ints, nil
+}
+
+func parseBEDLine(line string, pos pars.Position) (string, gts.Feature, error) {
+	cols := strings.Fields(line)
+	if len(cols) < 3 {
+		what := fmt.Sprintf("expected at least 3 columns in BED line, got %d", len(cols))
+		return "", gts.Feature{}, pars.NewError(what, pos)
+	}
+
+	start, err := strconv.Atoi(cols[1])
+	if err != nil {
+		return "", gts.Feature{}, pars.NewError("expected integer in chromStart column", pos)
+	}
+	end, err := strconv.Atoi(cols[2])
+	if err != nil {
+		return "", gts.Feature{}, pars.NewError("expected integer in chromEnd column", pos)
+	}
+	if start < 0 || end < start {
+		what := fmt.Sprintf("invalid feature range [%d, %d)", start, end)
+		return "", gts.Feature{}, pars.NewError(what, pos)
+	}
+
+	key := "misc_feature"
+	if len(cols) > 3 && cols[3] != "." {
+		key = cols[3]
+	}
+
+	locs := []gts.Location{}
+	if len(cols) >= 12 {
+		count, err := strconv.Atoi(cols[9])
+		if err != nil {
+			return "", gts.Feature{}, pars.NewError("expected integer in blockCount column", pos)
+		}
+		sizes, err := parseBEDInts(cols[10], count)
+		if err != nil {
+			return "", gts.Feature{}, pars.NewError(fmt.Sprintf("in blockSizes column: %v", err), pos)
+		}
+		starts, err := parseBEDInts(cols[11], count)
+		if err != nil {
+			return "", gts.Feature{}, pars.NewError(fmt.Sprintf("in blockStarts column: %v", err), pos)
+		}
+		for i := range sizes {
+			head := start + starts[i]
+			tail := head + sizes[i]
+			if tail > end {
+				return "", gts.Feature{}, pars.NewError("block exceeds the feature range", pos)
+			}
+			locs = append(locs, bedLocation(head, tail))
+		}
+	} else {
+		locs = append(locs, bedLocation(start, end))
+	}
+
+	var loc gts.Location = gts.Joined(locs)
+	if len(locs) == 1 {
+		loc = locs[0]
+	}
+
+	if len(cols) > 5 && cols[5] == "-" {
+		loc = loc.Complement()
+	}
+
+	return cols[0], gts.NewFeature(key, loc, gts.Props{}), nil
+}
+
+func bedLocation(start, end int) gts.Location {
+	switch end - start {
+	case 0:
+		return gts.Between(start)
+	case 1:
+		return gts.Point(start)
+	default:
+		return gts.Range(start, end)
+	}
+}
+
+// BEDParser attempts to parse the lines of a BED file. Header lines starting
+// with `#`, `track`, or `browser` are ignored.
+func BEDParser(state *pars.State, result *pars.Result) error {
+	table := FeatureTable{}
+	index := make(map[string]int)
+
+	for state.Request(1) == nil {
+		pos := state.Position()
+		pars.Line(state, result)
+		line := strings.TrimRight(string(result.Token), "\r")
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			continue
+		case strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "track"), strings.HasPrefix(line, "browser"):
+			continue
+		}
+
+		chrom, f, err := parseBEDLine(line, pos)
+		if err != nil {
+			return err
+		}
+
+		i, ok := index[chrom]
+		if !ok {
+			i = len(table)
+			index[chrom] = i
+			table = append(table, FeatureEntry{chrom, nil})
+		}
+		table[i].Features = append(table[i].Features, f)
+	}
+
+	if len(table) == 0 {
+		return pars.NewError("expected at least one BED line", state.Position())
+	}
+
+	state.Clear()
+	result.SetValue(table)
+	return nil
+}
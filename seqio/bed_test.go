@@ -0,0 +1,193 @@
+package seqio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/internal/testutils"
+	"github.com/go-pars/pars"
+)
+
+func TestBEDWriter(t *testing.T) {
+	p := []byte(strings.Repeat("atgc", 25))
+	ff := []gts.Feature{
+		gts.NewFeature("source", gts.Range(0, len(p)), gts.Props{}),
+		gts.NewFeature("gene", gts.Range(9, 40).Complement(), gts.Props{}),
+		gts.NewFeature("CDS", gts.Join(gts.Range(9, 20), gts.Range(29, 40)), gts.Props{}),
+		gts.NewFeature("misc_feature", gts.Join(gts.Range(0, 5), gts.Range(10, 15).Complement()), gts.Props{}),
+		gts.NewFeature("variation", gts.Point(49), gts.Props{}),
+		gts.NewFeature("misc_feature", gts.Joined{}, gts.Props{}),
+	}
+	seq := gts.New("seq desc", ff, p)
+
+	exp := "" +
+		"seq\t9\t40\tgene\t0\t-\t9\t9\t0\t1\t31,\t0,\n" +
+		"seq\t9\t40\tCDS\t0\t+\t9\t40\t0\t2\t11,11,\t0,20,\n" +
+		"seq\t0\t15\tmisc_feature\t0\t.\t0\t0\t0\t2\t5,5,\t0,10,\n" +
+		"seq\t49\t50\tvariation\t0\t+\t49\t49\t0\t1\t1,\t0,\n" +
+		"# skipped misc_feature join() in seq: no region to represent\n"
+
+	b := strings.Builder{}
+	n, err := NewWriter(&b, BEDFile).WriteSeq(seq)
+	if n != len(exp) || err != nil {
+		t.Errorf("w.WriteSeq(seq) = (%d, %v), want (%d, nil)", n, err, len(exp))
+	}
+	testutils.DiffLine(t, exp, b.String())
+
+	state := pars.FromString(b.String())
+	result, err := pars.AsParser(BEDParser).Parse(state)
+	if err != nil {
+		t.Errorf("parser returned %v", err)
+		return
+	}
+
+	table := result.Value.(FeatureTable)
+	gg, ok := table.Lookup("seq")
+	if !ok || len(gg) != 4 {
+		t.Errorf("table.Lookup(%q) = (%v, %t)", "seq", gg, ok)
+		return
+	}
+
+	testutils.Equals(t, gg[0], ff[1])
+	testutils.Equals(t, gg[1], ff[2])
+	testutils.Equals(t, gg[2].Loc, gts.Location(gts.Joined{gts.Range(0, 5), gts.Range(10, 15)}))
+	testutils.Equals(t, gg[3], ff[4])
+}
+
+func TestBEDWriterRemote(t *testing.T) {
+	p := []byte(strings.Repeat("atgc", 25))
+	remote := gts.Remote{Accession: "J00194.1", Location: gts.Range(99, 202)}
+	ff := []gts.Feature{
+		gts.NewFeature("misc_feature", remote, gts.Props{}),
+		gts.NewFeature("misc_feature", gts.Join(gts.Range(9, 20), remote), gts.Props{}),
+	}
+	seq := gts.New("seq desc", ff, p)
+
+	exp := "" +
+		"# skipped misc_feature J00194.1:100..202 in seq: remote location in J00194.1\n" +
+		"# omitted remote parts of misc_feature join(10..20,J00194.1:100..202) in seq: J00194.1\n" +
+		"seq\t9\t20\tmisc_feature\t0\t+\t9\t9\t0\t1\t11,\t0,\n"
+
+	b := strings.Builder{}
+	n, err := NewWriter(&b, BEDFile).WriteSeq(seq)
+	if n != len(exp) || err != nil {
+		t.Errorf("w.WriteSeq(seq) = (%d, %v), want (%d, nil)", n, err, len(exp))
+	}
+	testutils.DiffLine(t, exp, b.String())
+
+	state := pars.FromString(b.String())
+	result, err := pars.AsParser(BEDParser).Parse(state)
+	if err != nil {
+		t.Errorf("parser returned %v", err)
+		return
+	}
+
+	table := result.Value.(FeatureTable)
+	gg, ok := table.Lookup("seq")
+	if !ok || len(gg) != 1 {
+		t.Errorf("table.Lookup(%q) = (%v, %t)", "seq", gg, ok)
+		return
+	}
+	testutils.Equals(t, gg[0].Loc, gts.Location(gts.Range(9, 20)))
+}
+
+func TestBEDWriterCircular(t *testing.T) {
+	p := []byte(strings.Repeat("atgc", 1500))
+	wrapped := gts.Join(gts.Range(4999, 6000), gts.Range(0, 100))
+	ff := []gts.Feature{
+		gts.NewFeature("CDS", wrapped, gts.Props{}),
+		gts.NewFeature("CDS", wrapped.Complement(), gts.Props{}),
+		gts.NewFeature("CDS", gts.Joined{gts.Range(0, 100).Complement(), gts.Range(4999, 6000).Complement()}, gts.Props{}),
+	}
+	seq := gts.WithTopology(gts.New("seq desc", ff, p), gts.Circular)
+
+	exp := "" +
+		"seq\t0\t6000\tCDS\t0\t+\t0\t6000\t0\t2\t1001,100,\t4999,0,\n" +
+		"seq\t0\t6000\tCDS\t0\t-\t0\t6000\t0\t2\t1001,100,\t4999,0,\n" +
+		"seq\t0\t6000\tCDS\t0\t-\t0\t6000\t0\t2\t1001,100,\t4999,0,\n"
+
+	b := strings.Builder{}
+	if _, err := NewWriter(&b, BEDFile).WriteSeq(seq); err != nil {
+		t.Errorf("w.WriteSeq(seq): %v", err)
+	}
+	testutils.DiffLine(t, exp, b.String())
+
+	state := pars.FromString(b.String())
+	result, err := pars.AsParser(BEDParser).Parse(state)
+	if err != nil {
+		t.Errorf("parser returned %v", err)
+		return
+	}
+
+	table := result.Value.(FeatureTable)
+	gg, ok := table.Lookup("seq")
+	if !ok || len(gg) != len(ff) {
+		t.Errorf("table.Lookup(%q) = (%v, %t)", "seq", gg, ok)
+		return
+	}
+
+	testutils.Equals(t, gg[0].Loc, ff[0].Loc)
+	testutils.Equals(t, gg[1].Loc, ff[1].Loc)
+	testutils.Equals(t, gg[2].Loc, ff[1].Loc)
+
+	// The strand-ordered sequence of the CDS is retained.
+	for i, g := range gg {
+		out := g.Loc.Region().Locate(seq)
+		exp := ff[i].Loc.Region().Locate(seq)
+		testutils.Equals(t, out.Bytes(), exp.Bytes())
+	}
+}
+
+func TestBEDParser(t *testing.T) {
+	in := "" +
+		"browser position chr7:127471196-127495720\n" +
+		"track name=\"ItemRGBDemo\" itemRgb=\"On\"\n" +
+		"# comment\n" +
+		"chr7\t127471196\t127472363\n" +
+		"chr7\t127472363\t127473530\tPos2\t0\t-\n" +
+		"chr8 0 10 . 0 + 0 0 0 1 9 1\n"
+
+	state := pars.FromString(in)
+	result, err := pars.AsParser(BEDParser).Parse(state)
+	if err != nil {
+		t.Errorf("parser returned %v", err)
+		return
+	}
+
+	table := result.Value.(FeatureTable)
+	if len(table) != 2 {
+		t.Errorf("len(table) = %d, want 2", len(table))
+		return
+	}
+
+	ff := table[0].Features
+	testutils.Equals(t, table[0].SeqID, "chr7")
+	testutils.Equals(t, ff[0], gts.NewFeature("misc_feature", gts.Range(127471196, 127472363), gts.Props{}))
+	testutils.Equals(t, ff[1], gts.NewFeature("Pos2", gts.Range(127472363, 127473530).Complement(), gts.Props{}))
+
+	gg := table[1].Features
+	testutils.Equals(t, gg[0].Loc, gts.Location(gts.Range(1, 10)))
+}
+
+var bedFailTests = []string{
+	"",
+	"chr7\t127471196\n",
+	"chr7\tfoo\t127472363\n",
+	"chr7\t127471196\tbar\n",
+	"chr7\t127472363\t127471196\n",
+	"chr8\t0\t10\t.\t0\t+\t0\t0\t0\tfoo\t9\t1\n",
+	"chr8\t0\t10\t.\t0\t+\t0\t0\t0\t2\t9\t1\n",
+	"chr8\t0\t10\t.\t0\t+\t0\t0\t0\t1\t9\tx\n",
+	"chr8\t0\t10\t.\t0\t+\t0\t0\t0\t1\t10\t1\n",
+}
+
+func TestBEDParserFail(t *testing.T) {
+	parser := pars.AsParser(BEDParser)
+	for _, in := range bedFailTests {
+		state := pars.FromString(in)
+		if err := parser(state, pars.Void); err == nil {
+			t.Errorf("while parsing`\n%s\n`: expected error", in)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package seqio
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression represents a compression format.
+type Compression int
+
+// Available compression formats in GTS.
+const (
+	NoCompression Compression = iota
+	GzipCompression
+	BGZFCompression
+	ZstdCompression
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// ErrCompressedIndex is returned when building an index for a compressed file.
+// The byte offsets of the records in a compressed file cannot be used to read
+// the records directly, so the file must be decompressed before indexing.
+var ErrCompressedIndex = errors.New("compressed files cannot be indexed: decompress the file first")
+
+// isCompressed tests if the buffered input starts with the magic bytes of a
+// gzip (including BGZF) or zstd stream.
+func isCompressed(br *bufio.Reader) bool {
+	magic, _ := br.Peek(len(zstdMagic))
+	return bytes.HasPrefix(magic, gzipMagic) || bytes.HasPrefix(magic, zstdMagic)
+}
+
+// DetectCompression returns the Compression associated to the extension of
+// the given filename.
+func DetectCompression(filename string) Compression {
+	switch filepath.Ext(filename) {
+	case ".gz", ".gzip":
+		return GzipCompression
+	case ".bgz", ".bgzf":
+		return BGZFCompression
+	case ".zst", ".zstd":
+		return ZstdCompression
+	default:
+		return NoCompression
+	}
+}
+
+// trimCompression removes the compression extension of the given filename.
+func trimCompression(filename string) string {
+	if DetectCompression(filename) != NoCompression {
+		return filename[:len(filename)-len(filepath.Ext(filename))]
+	}
+	return filename
+}
+
+type zstdReadCloser struct {
+	*zstd.Decoder
+}
+
+func (r zstdReadCloser) Close() error {
+	r.Decoder.Close()
+	return nil
+}
+
+// NewDecompressor returns a reader which decompresses the content of the
+// given reader if it starts with the magic bytes of a gzip or zstd stream.
+// BGZF files are decompressed as multistream gzip files. Otherwise, the
+// content will be read as is.
+func NewDecompressor(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(magic, zstdMagic):
+		d, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return zstdReadCloser{d}, nil
+	default:
+		return ioutil.NopCloser(br), nil
+	}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// NewCompressor returns a writer which compresses the data written to it in
+// the given compression format. The writer must be closed to flush the data.
+// Closing the writer will not close the underlying writer.
+func NewCompressor(w io.Writer, c Compression) (io.WriteCloser, error) {
+	switch c {
+	case GzipCompression:
+		return gzip.NewWriter(w), nil
+	case BGZFCompression:
+		return &bgzfWriter{w: w}, nil
+	case ZstdCompression:
+		return zstd.NewWriter(w)
+	default:
+		return nopWriteCloser{w}, nil
+	}
+}
+
+// bgzfBlockSize is the maximum number of bytes in a single BGZF block, chosen
+// so that a block will fit within 64 KiB after compression.
+const bgzfBlockSize = 0xff00
+
+// bgzfEOF is the empty block marking the end of a BGZF file.
+var bgzfEOF = []byte{
+	0x1f, 0x8b, 0x08, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0x06, 0x00,
+	0x42, 0x43, 0x02, 0x00, 0x1b, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00,
+}
+
+type bgzfWriter struct {
+	w      io.Writer
+	buf    []byte
+	closed bool
+}
+
+func (z *bgzfWriter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		k := bgzfBlockSize - len(z.buf)
+		if len(p) < k {
+			k = len(p)
+		}
+		z.buf = append(z.buf, p[:k]...)
+		p, n = p[k:], n+k
+		if len(z.buf) == bgzfBlockSize {
+			if err := z.flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (z *bgzfWriter) flush() error {
+	if len(z.buf) == 0 {
+		return nil
+	}
+
+	b := bytes.Buffer{}
+	fw, err := flate.NewWriter(&b, flate.DefaultCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := fw.Write(z.buf); err != nil {
+		return err
+	}
+	if err := fw.Close(); err != nil {
+		return err
+	}
+
+	header := []byte{
+		0x1f, 0x8b, 0x08, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0x06, 0x00,
+		0x42, 0x43, 0x02, 0x00, 0x00, 0x00,
+	}
+	binary.LittleEndian.PutUint16(header[16:], uint16(len(header)+b.Len()+8-1))
+
+	trailer := make([]byte, 8)
+	binary.LittleEndian.PutUint32(trailer, crc32.ChecksumIEEE(z.buf))
+	binary.LittleEndian.PutUint32(trailer[4:], uint32(len(z.buf)))
+
+	z.buf = z.buf[:0]
+
+	for _, p := range [][]byte{header, b.Bytes(), trailer} {
+		if _, err := z.w.Write(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close flushes the remaining data and writes the BGZF end-of-file marker.
+func (z *bgzfWriter) Close() error {
+	if z.closed {
+		return nil
+	}
+	z.closed = true
+	if err := z.flush(); err != nil {
+		return err
+	}
+	_, err := z.w.Write(bgzfEOF)
+	return err
+}
@@ -0,0 +1,139 @@
+package seqio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/go-gts/gts/internal/testutils"
+)
+
+var detectCompressionTests = []struct {
+	in  string
+	out Compression
+}{
+	{"foo.gb", NoCompression},
+	{"foo.gb.gz", GzipCompression},
+	{"foo.gb.gzip", GzipCompression},
+	{"foo.gb.bgz", BGZFCompression},
+	{"foo.gb.bgzf", BGZFCompression},
+	{"foo.gb.zst", ZstdCompression},
+	{"foo.gb.zstd", ZstdCompression},
+}
+
+func TestDetectCompression(t *testing.T) {
+	for _, tt := range detectCompressionTests {
+		out := DetectCompression(tt.in)
+		if out != tt.out {
+			t.Errorf("DetectCompression(%q) = %v, want %v", tt.in, out, tt.out)
+		}
+	}
+}
+
+func TestCompression(t *testing.T) {
+	in := testutils.ReadTestfile(t, "NC_001422.gb")
+	in = strings.Repeat(in, 16)
+
+	for _, c := range []Compression{NoCompression, GzipCompression, BGZFCompression, ZstdCompression} {
+		buf := bytes.Buffer{}
+		w, err := NewCompressor(&buf, c)
+		if err != nil {
+			t.Fatalf("NewCompressor(w, %v): %v", c, err)
+		}
+		if _, err := w.Write([]byte(in)); err != nil {
+			t.Errorf("w.Write: %v", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Errorf("w.Close: %v", err)
+		}
+
+		p := buf.Bytes()
+		if c == NoCompression {
+			testutils.Equals(t, string(p), in)
+		}
+		if c == BGZFCompression && !bytes.HasSuffix(p, bgzfEOF) {
+			t.Error("BGZF output is missing the EOF marker")
+		}
+
+		r, err := NewDecompressor(bytes.NewReader(p))
+		if err != nil {
+			t.Fatalf("NewDecompressor: %v", err)
+		}
+		out, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Errorf("ioutil.ReadAll: %v", err)
+		}
+		if err := r.Close(); err != nil {
+			t.Errorf("r.Close: %v", err)
+		}
+		testutils.Equals(t, string(out), in)
+	}
+}
+
+func TestBGZFCompression(t *testing.T) {
+	in := strings.Repeat("ACGT", bgzfBlockSize)
+
+	buf := bytes.Buffer{}
+	w, _ := NewCompressor(&buf, BGZFCompression)
+	w.Write([]byte(in))
+	w.Close()
+
+	// Each block must be a standalone gzip member.
+	r, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	r.Multistream(false)
+
+	blocks := 0
+	out := []byte{}
+	for {
+		p, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatalf("ioutil.ReadAll: %v", err)
+		}
+		if len(p) > bgzfBlockSize {
+			t.Errorf("block %d has %d bytes, expected at most %d", blocks, len(p), bgzfBlockSize)
+		}
+		out = append(out, p...)
+		blocks++
+		if err := r.Reset(&buf); err != nil {
+			break
+		}
+		r.Multistream(false)
+	}
+
+	testutils.Equals(t, string(out), in)
+	testutils.Equals(t, blocks, 5) // 4 data blocks and the EOF marker
+}
+
+func TestBuildIndexCompressed(t *testing.T) {
+	gb := testutils.ReadTestfile(t, "NC_001422.gb")
+	fasta := testutils.ReadTestfile(t, "NC_001422.fasta")
+
+	compress := func(in string, c Compression) []byte {
+		buf := bytes.Buffer{}
+		w, err := NewCompressor(&buf, c)
+		if err != nil {
+			t.Fatalf("NewCompressor(%v): %v", c, err)
+		}
+		if _, err := w.Write([]byte(in)); err != nil {
+			t.Fatalf("w.Write(): %v", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("w.Close(): %v", err)
+		}
+		return buf.Bytes()
+	}
+
+	for _, c := range []Compression{GzipCompression, BGZFCompression, ZstdCompression} {
+		if _, err := BuildIndex(bytes.NewReader(compress(gb, c))); err != ErrCompressedIndex {
+			t.Errorf("BuildIndex(%v) = %v, want %v", c, err, ErrCompressedIndex)
+		}
+		if _, err := BuildFaidx(bytes.NewReader(compress(fasta, c))); err != ErrCompressedIndex {
+			t.Errorf("BuildFaidx(%v) = %v, want %v", c, err, ErrCompressedIndex)
+		}
+	}
+}
@@ -0,0 +1,655 @@
+package seqio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-ascii/ascii"
+	"github.com/go-gts/gts"
+	"github.com/go-pars/pars"
+	"github.com/go-wrap/wrap"
+)
+
+const emblLineWidth = 75
+
+const emblEndMessage = "expected `//` at the end of EMBL record"
+
+// EMBL represents an EMBL sequence record. The metadata is shared with the
+// GenBank format so that records can be freely converted between the two.
+type EMBL struct {
+	Fields GenBankFields
+	Table  gts.FeatureSlice
+	Data   []byte
+}
+
+// Info returns the metadata of the sequence.
+func (emb EMBL) Info() interface{} {
+	return emb.Fields
+}
+
+// Features returns the feature table of the sequence.
+func (emb EMBL) Features() gts.FeatureSlice {
+	return emb.Table
+}
+
+// Len returns the length of the sequence.
+func (emb EMBL) Len() int {
+	return len(emb.Data)
+}
+
+// Bytes returns the byte representation of the sequence.
+func (emb EMBL) Bytes() []byte {
+	return emb.Data
+}
+
+// WithInfo creates a shallow copy of the given Sequence object and swaps the
+// metadata with the given value.
+func (emb EMBL) WithInfo(info interface{}) gts.Sequence {
+	switch v := info.(type) {
+	case GenBankFields:
+		return EMBL{v, emb.Table, emb.Data}
+	default:
+		return gts.New(v, emb.Features(), emb.Bytes())
+	}
+}
+
+// WithFeatures creates a shallow copy of the given Sequence object and swaps
+// the feature table with the given features.
+func (emb EMBL) WithFeatures(ff []gts.Feature) gts.Sequence {
+	return EMBL{emb.Fields, ff, emb.Data}
+}
+
+// WithBytes creates a shallow copy of the given Sequence object and swaps the
+// byte representation with the given byte slice.
+func (emb EMBL) WithBytes(p []byte) gts.Sequence {
+	return EMBL{emb.Fields, emb.Table, p}
+}
+
+// Topology returns the topology of the sequence.
+func (emb EMBL) Topology() gts.Topology {
+	return emb.Fields.Topology
+}
+
+// WithTopology creates a shallow copy of the given Sequence object and swaps
+// the topology value with the given value.
+func (emb EMBL) WithTopology(t gts.Topology) gts.Sequence {
+	info := emb.Fields
+	info.Topology = t
+	return emb.WithInfo(info)
+}
+
+func emblField(code, value string) string {
+	prefix := fmt.Sprintf("%-5s", code)
+	return prefix + AddPrefix(value, prefix)
+}
+
+func emblPrimaryAccession(gbf GenBankFields) string {
+	if fields := strings.Fields(gbf.Accession); len(fields) > 0 {
+		return fields[0]
+	}
+	return gbf.LocusName
+}
+
+func emblSequenceVersion(gbf GenBankFields) string {
+	if i := strings.LastIndexByte(gbf.Version, '.'); i >= 0 {
+		return gbf.Version[i+1:]
+	}
+	return ""
+}
+
+func emblReferencePositions(info, prefix string) string {
+	result, err := parseReferenceInfo(prefix).Parse(pars.FromString(info))
+	if err != nil {
+		return info
+	}
+	locs := result.Value.([]gts.Ranged)
+	ss := make([]string, len(locs))
+	for i, loc := range locs {
+		ss[i] = fmt.Sprintf("%d-%d", loc.Start+1, loc.End)
+	}
+	return strings.Join(ss, ", ")
+}
+
+func formatEMBLSequence(p []byte) string {
+	b := strings.Builder{}
+
+	counts := make(map[byte]int)
+	for _, c := range bytes.ToLower(p) {
+		counts[c]++
+	}
+	other := len(p) - counts['a'] - counts['c'] - counts['g'] - counts['t']
+	b.WriteString(fmt.Sprintf(
+		"SQ   Sequence %d BP; %d A; %d C; %d G; %d T; %d other;\n",
+		len(p), counts['a'], counts['c'], counts['g'], counts['t'], other,
+	))
+
+	for i := 0; i < len(p); i += 60 {
+		blocks := make([]string, 0, 6)
+		for j := i; j < i+60 && j < len(p); j += 10 {
+			blocks = append(blocks, string(p[j:gts.Min(j+10, len(p))]))
+		}
+		line := strings.Join(blocks, " ")
+		b.WriteString(fmt.Sprintf("     %-65s%10d\n", line, gts.Min(i+60, len(p))))
+	}
+
+	return b.String()
+}
+
+// String satisifes the fmt.Stringer interface.
+func (emb EMBL) String() string {
+	b := strings.Builder{}
+	gbf := emb.Fields
+
+	length := len(emb.Data)
+	if length == 0 {
+		length = gbf.Contig.Region.Len()
+	}
+
+	accession := emblPrimaryAccession(gbf)
+	id := fmt.Sprintf(
+		"ID   %s; SV %s; %s; %s; STD; %s; %d BP.",
+		accession, emblSequenceVersion(gbf), gbf.Topology,
+		gbf.Molecule, gbf.Division, length,
+	)
+	b.WriteString(id + "\n")
+	b.WriteString("XX\n")
+
+	accessions := strings.Fields(gbf.Accession)
+	if len(accessions) == 0 {
+		accessions = []string{accession}
+	}
+	b.WriteString("AC   " + strings.Join(accessions, "; ") + ";\n")
+	b.WriteString("XX\n")
+
+	project := gbf.DBLink.Get("BioProject")
+	for _, value := range project {
+		b.WriteString(fmt.Sprintf("PR   Project:%s;\n", value))
+	}
+	if len(project) > 0 {
+		b.WriteString("XX\n")
+	}
+
+	date := strings.ToUpper(gbf.Date.ToTime().Format("02-Jan-2006"))
+	b.WriteString("DT   " + date + "\n")
+	b.WriteString("XX\n")
+
+	b.WriteString(emblField("DE", gbf.Definition) + "\n")
+	b.WriteString("XX\n")
+
+	keywords := wrap.Space(strings.Join(gbf.Keywords, "; ")+".", emblLineWidth)
+	b.WriteString(emblField("KW", keywords) + "\n")
+	b.WriteString("XX\n")
+
+	species := gbf.Source.Species
+	if species == "" {
+		species = gbf.Source.Name
+	}
+	b.WriteString(emblField("OS", wrap.Space(species, emblLineWidth)) + "\n")
+	taxon := wrap.Space(strings.Join(gbf.Source.Taxon, "; ")+".", emblLineWidth)
+	b.WriteString(emblField("OC", taxon) + "\n")
+	b.WriteString("XX\n")
+
+	counter := gbf.Molecule.Counter()
+	for _, ref := range gbf.References {
+		b.WriteString(fmt.Sprintf("RN   [%d]\n", ref.Number))
+		if ref.Comment != "" {
+			b.WriteString(emblField("RC", ref.Comment) + "\n")
+		}
+		if ref.Info != "" {
+			positions := emblReferencePositions(ref.Info, counter)
+			b.WriteString(emblField("RP", positions) + "\n")
+		}
+		keys := make([]string, 0, len(ref.Xref))
+		for key := range ref.Xref {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			b.WriteString(fmt.Sprintf("RX   %s; %s.\n", key, ref.Xref[key]))
+		}
+		if ref.Group != "" {
+			b.WriteString(emblField("RG", ref.Group) + "\n")
+		}
+		if ref.Authors != "" {
+			b.WriteString(emblField("RA", ref.Authors+";") + "\n")
+		}
+		switch ref.Title {
+		case "":
+			b.WriteString("RT   ;\n")
+		default:
+			b.WriteString(emblField("RT", "\""+ref.Title+"\";") + "\n")
+		}
+		if ref.Journal != "" {
+			b.WriteString(emblField("RL", ref.Journal) + "\n")
+		}
+		b.WriteString("XX\n")
+	}
+
+	dblink := false
+	for _, pair := range gbf.DBLink {
+		if pair.Key != "BioProject" {
+			b.WriteString(fmt.Sprintf("DR   %s; %s.\n", pair.Key, pair.Value))
+			dblink = true
+		}
+	}
+	if dblink {
+		b.WriteString("XX\n")
+	}
+
+	for _, comment := range gbf.Comments {
+		b.WriteString(emblField("CC", comment) + "\n")
+		b.WriteString("XX\n")
+	}
+
+	b.WriteString("FH   Key             Location/Qualifiers\n")
+	b.WriteString("FH\n")
+	if len(emb.Table) > 0 {
+		fmtr := INSDCFormatter{emb.Table, "FT   ", 21}
+		fmtr.WriteTo(&b)
+		b.WriteByte('\n')
+	}
+	b.WriteString("XX\n")
+
+	if gbf.Contig.String() != "" {
+		b.WriteString(fmt.Sprintf("CO   %s\n", gbf.Contig))
+		b.WriteString("XX\n")
+	}
+
+	if len(emb.Data) > 0 {
+		b.WriteString(formatEMBLSequence(emb.Data))
+	}
+
+	b.WriteString("//\n")
+
+	return b.String()
+}
+
+// WriteTo satisfies the io.WriterTo interface.
+func (emb EMBL) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, emb.String())
+	return int64(n), err
+}
+
+// EMBLWriter writes a gts.Sequence to an io.Writer in EMBL format.
+type EMBLWriter struct {
+	w io.Writer
+}
+
+// WriteSeq satisfies the seqio.SeqWriter interface.
+func (w EMBLWriter) WriteSeq(seq gts.Sequence) (int, error) {
+	switch v := seq.(type) {
+	case EMBL:
+		n, err := v.WriteTo(w.w)
+		return int(n), err
+	case *EMBL:
+		return w.WriteSeq(*v)
+	default:
+		switch info := v.Info().(type) {
+		case GenBankFields:
+			emb := EMBL{info, v.Features(), v.Bytes()}
+			return w.WriteSeq(emb)
+		default:
+			return 0, fmt.Errorf("gts does not know how to format a sequence with metadata of type `%T` as EMBL", info)
+		}
+	}
+}
+
+type emblLine struct {
+	code string
+	body string
+	pos  pars.Position
+}
+
+func splitEMBLLine(p []byte, pos pars.Position) emblLine {
+	s := string(p)
+	if len(s) < 5 {
+		return emblLine{strings.TrimSpace(s), "", pos}
+	}
+	return emblLine{strings.TrimSpace(s[:2]), s[5:], pos}
+}
+
+func parseEMBLID(state *pars.State, result *pars.Result) (GenBankFields, int, error) {
+	if err := pars.String("ID   ")(state, result); err != nil {
+		return GenBankFields{}, 0, err
+	}
+	pos := state.Position()
+	pars.Line(state, result)
+
+	body := strings.TrimSuffix(string(result.Token), ".")
+	fields := strings.Split(body, ";")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	if len(fields) != 7 {
+		return GenBankFields{}, 0, pars.NewError("expected 7 fields in ID line", pos)
+	}
+
+	accession := fields[0]
+	version := strings.TrimSpace(strings.TrimPrefix(fields[1], "SV"))
+	if version != "" {
+		version = accession + "." + version
+	}
+
+	topology, err := gts.AsTopology(fields[2])
+	if err != nil {
+		return GenBankFields{}, 0, pars.NewError(err.Error(), pos)
+	}
+
+	molecule, err := gts.AsMolecule(fields[3])
+	if err != nil {
+		switch {
+		case strings.Contains(fields[3], "RNA"):
+			molecule = gts.RNA
+		default:
+			molecule = gts.DNA
+		}
+	}
+
+	length, err := strconv.Atoi(strings.TrimSuffix(fields[6], " BP"))
+	if err != nil {
+		return GenBankFields{}, 0, pars.NewError("expected sequence length", pos)
+	}
+
+	gbf := GenBankFields{
+		LocusName: accession,
+		Molecule:  molecule,
+		Topology:  topology,
+		Division:  fields[5],
+		Accession: accession,
+		Version:   version,
+	}
+
+	return gbf, length, nil
+}
+
+func mergeEMBLLocations(lines []string) []string {
+	merged := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if len(line) > 5 && line[5] != ' ' {
+			depth := strings.Count(line, "(") - strings.Count(line, ")")
+			for depth > 0 && i+1 < len(lines) {
+				next := strings.TrimSpace(lines[i+1][2:])
+				if strings.HasPrefix(next, "/") {
+					break
+				}
+				line += next
+				depth += strings.Count(next, "(") - strings.Count(next, ")")
+				i++
+			}
+		}
+		merged = append(merged, line)
+	}
+	return merged
+}
+
+func parseEMBLFeatureTable(lines []string, pos pars.Position) ([]gts.Feature, error) {
+	s := strings.Join(mergeEMBLLocations(lines), "\n") + "\n"
+	state := pars.FromString(s)
+	result, err := INSDCTableParser("FT").Parse(state)
+	if err != nil {
+		return nil, err
+	}
+	if pars.End(state, pars.Void) != nil {
+		where := state.Position()
+		where.Line += pos.Line
+		return nil, pars.NewError("unexpected line in feature table", where)
+	}
+	return result.Value.([]gts.Feature), nil
+}
+
+func parseEMBLContig(s string) (Contig, bool) {
+	if !strings.HasPrefix(s, "join(") || !strings.HasSuffix(s, ")") {
+		return Contig{}, false
+	}
+	s = s[5 : len(s)-1]
+	i := strings.IndexByte(s, ':')
+	if i < 0 {
+		return Contig{}, false
+	}
+	accession := s[:i]
+	bounds := strings.Split(s[i+1:], "..")
+	if len(bounds) != 2 {
+		return Contig{}, false
+	}
+	head, err := strconv.Atoi(bounds[0])
+	if err != nil {
+		return Contig{}, false
+	}
+	tail, err := strconv.Atoi(bounds[1])
+	if err != nil {
+		return Contig{}, false
+	}
+	return Contig{accession, gts.Segment{head - 1, tail}}, true
+}
+
+func emblSequenceParser(state *pars.State, result *pars.Result) error {
+	w := bytes.Buffer{}
+	for {
+		if err := state.Request(1); err != nil {
+			return pars.NewError(emblEndMessage, state.Position())
+		}
+		pars.Line(state, result)
+		line := result.Token
+		if bytes.Equal(line, []byte("//")) {
+			break
+		}
+		for _, c := range line {
+			if !ascii.IsSpace(c) && !ascii.IsDigit(c) {
+				w.WriteByte(c)
+			}
+		}
+	}
+	result.SetToken(w.Bytes())
+	return nil
+}
+
+// EMBLParser attempts to parse a single EMBL record.
+func EMBLParser(state *pars.State, result *pars.Result) error {
+	gbf, length, err := parseEMBLID(state, result)
+	if err != nil {
+		return err
+	}
+
+	state.Clear()
+
+	lines := []emblLine{}
+	sequence := false
+
+	var data []byte
+
+	for !sequence {
+		if err := state.Request(1); err != nil {
+			return pars.NewError(emblEndMessage, state.Position())
+		}
+		pos := state.Position()
+		pars.Line(state, result)
+		line := splitEMBLLine(result.Token, pos)
+		switch line.code {
+		case "//":
+			sequence = true
+		case "SQ":
+			if err := emblSequenceParser(state, result); err != nil {
+				return err
+			}
+			data = result.Token
+			sequence = true
+		default:
+			lines = append(lines, line)
+		}
+	}
+
+	emb := EMBL{Data: data}
+	if len(emb.Data) > 0 && len(emb.Data) != length {
+		what := fmt.Sprintf("expected %d bases in sequence, got %d", length, len(emb.Data))
+		return pars.NewError(what, state.Position())
+	}
+
+	take := func(i int) ([]string, int) {
+		code := lines[i].code
+		ss := []string{}
+		for i < len(lines) && lines[i].code == code {
+			ss = append(ss, lines[i].body)
+			i++
+		}
+		return ss, i
+	}
+
+	accessions := []string{}
+	var ref *Reference
+
+	counter := gbf.Molecule.Counter()
+	referenceParser := parseReferenceInfo(counter)
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		if line.code != "RN" && strings.HasPrefix(line.code, "R") && ref == nil {
+			return pars.NewError("expected reference number", line.pos)
+		}
+
+		ss, j := take(i)
+		body := strings.Join(ss, "\n")
+
+		switch line.code {
+		case "AC":
+			for _, s := range strings.Split(strings.Join(ss, " "), ";") {
+				if s = strings.TrimSpace(s); s != "" {
+					accessions = append(accessions, s)
+				}
+			}
+
+		case "PR":
+			for _, s := range ss {
+				s = strings.TrimSuffix(strings.TrimSpace(s), ";")
+				gbf.DBLink.Set("BioProject", strings.TrimPrefix(s, "Project:"))
+			}
+
+		case "DT":
+			for _, s := range ss {
+				if fields := strings.Fields(s); len(fields) > 0 {
+					if date, err := AsDate(fields[0]); err == nil {
+						gbf.Date = date
+					}
+				}
+			}
+
+		case "DE":
+			gbf.Definition = body
+
+		case "KW":
+			gbf.Keywords = FlatFileSplit(strings.Join(ss, " "))
+
+		case "OS":
+			gbf.Source.Species = strings.Join(ss, " ")
+			name := gbf.Source.Species
+			if k := strings.LastIndex(name, " ("); k >= 0 && strings.HasSuffix(name, ")") {
+				name = name[:k]
+			}
+			gbf.Source.Name = name
+
+		case "OC":
+			gbf.Source.Taxon = FlatFileSplit(strings.Join(ss, " "))
+
+		case "RN":
+			fields := strings.Fields(body)
+			if len(fields) == 0 {
+				return pars.NewError("expected reference number", line.pos)
+			}
+			number, err := strconv.Atoi(strings.Trim(fields[0], "[]"))
+			if err != nil {
+				return pars.NewError("expected reference number", line.pos)
+			}
+			gbf.References = append(gbf.References, Reference{Number: number})
+			ref = &gbf.References[len(gbf.References)-1]
+
+		case "RC":
+			ref.Comment = body
+
+		case "RP":
+			ranges := strings.Split(strings.Join(ss, " "), ",")
+			parts := make([]string, 0, len(ranges))
+			for _, s := range ranges {
+				bounds := strings.Split(strings.TrimSpace(s), "-")
+				if len(bounds) != 2 {
+					parts = nil
+					break
+				}
+				parts = append(parts, fmt.Sprintf("%s to %s", bounds[0], bounds[1]))
+			}
+			info := fmt.Sprintf("(%s %s)", counter, strings.Join(parts, "; "))
+			if _, err := referenceParser.Parse(pars.FromString(info)); err != nil {
+				info = body
+			}
+			ref.Info = info
+
+		case "RX":
+			for _, s := range ss {
+				if k := strings.IndexByte(s, ';'); k >= 0 {
+					if ref.Xref == nil {
+						ref.Xref = make(map[string]string)
+					}
+					key := s[:k]
+					value := strings.TrimSuffix(strings.TrimSpace(s[k+1:]), ".")
+					ref.Xref[key] = value
+				}
+			}
+
+		case "RG":
+			ref.Group = body
+
+		case "RA":
+			ref.Authors = strings.TrimSuffix(body, ";")
+
+		case "RT":
+			title := strings.TrimSuffix(body, ";")
+			ref.Title = strings.TrimPrefix(strings.TrimSuffix(title, "\""), "\"")
+
+		case "RL":
+			ref.Journal = body
+
+		case "DR":
+			for _, s := range ss {
+				if k := strings.IndexByte(s, ';'); k >= 0 && k+2 <= len(s) {
+					gbf.DBLink.Set(s[:k], strings.TrimSuffix(s[k+2:], "."))
+				}
+			}
+
+		case "CC":
+			gbf.Comments = append(gbf.Comments, body)
+
+		case "FT":
+			raw := make([]string, len(ss))
+			for k, s := range ss {
+				raw[k] = "FT   " + s
+			}
+			ff, err := parseEMBLFeatureTable(raw, line.pos)
+			if err != nil {
+				return err
+			}
+			emb.Table = ff
+
+		case "CO":
+			if contig, ok := parseEMBLContig(strings.Join(ss, "")); ok {
+				gbf.Contig = contig
+			}
+		}
+
+		if !strings.HasPrefix(line.code, "R") && line.code != "XX" {
+			ref = nil
+		}
+
+		i = j
+	}
+
+	if len(accessions) > 0 {
+		gbf.Accession = strings.Join(accessions, " ")
+	}
+
+	emb.Fields = gbf
+	result.SetValue(emb)
+	return nil
+}
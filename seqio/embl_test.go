@@ -0,0 +1,204 @@
+package seqio
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/internal/testutils"
+	"github.com/go-pars/pars"
+)
+
+func formatEMBLHelper(t *testing.T, seq gts.Sequence, in string) {
+	t.Helper()
+	b := strings.Builder{}
+	n, err := EMBLWriter{&b}.WriteSeq(seq)
+	if int(n) != len([]byte(in)) || err != nil {
+		t.Errorf("f.WriteSeq(seq) = (%d, %v), want %d, nil", n, err, len(in))
+	}
+	testutils.DiffLine(t, in, b.String())
+}
+
+func TestEMBLWithInterface(t *testing.T) {
+	length := 100
+
+	info := GenBankFields{
+		LocusName: "LOCUS_NAME",
+		Molecule:  gts.DNA,
+		Topology:  gts.Linear,
+		Division:  "UNA",
+		Date:      FromTime(time.Now()),
+	}
+
+	p := []byte(strings.Repeat("atgc", length))
+	props := gts.Props{}
+	props.Add("organism", "Genus species")
+	loc := gts.Range(0, len(p))
+	ff := []gts.Feature{gts.NewFeature("source", loc, props)}
+
+	in := EMBL{GenBankFields{}, nil, nil}
+	out := gts.WithInfo(in, info)
+	testutils.Equals(t, out, EMBL{info, nil, nil})
+
+	out = gts.WithFeatures(in, ff)
+	testutils.Equals(t, out, EMBL{GenBankFields{}, ff, nil})
+
+	out = gts.WithBytes(in, p)
+	testutils.Equals(t, out, EMBL{GenBankFields{}, nil, p})
+
+	out = gts.WithInfo(in, "info")
+	testutils.Equals(t, out, gts.New("info", nil, nil))
+
+	out = gts.WithTopology(in, gts.Circular)
+	top := out.(EMBL).Fields.Topology
+	if top != gts.Circular {
+		t.Errorf("topology is %q, expected %q", top, gts.Circular)
+	}
+	if top := gts.TopologyOf(out); top != gts.Circular {
+		t.Errorf("gts.TopologyOf(out) = %q, expected %q", top, gts.Circular)
+	}
+}
+
+func TestEMBLIO(t *testing.T) {
+	in := testutils.ReadTestfile(t, "NC_001422.embl")
+	state := pars.FromString(in)
+	parser := pars.AsParser(EMBLParser)
+
+	result, err := parser.Parse(state)
+	if err != nil {
+		t.Errorf("parser returned %v\nBuffer:\n%q", err, string(result.Token))
+		return
+	}
+
+	switch seq := result.Value.(type) {
+	case EMBL:
+		formatEMBLHelper(t, &seq, in)
+		cpy := gts.New(seq.Info(), seq.Features(), seq.Bytes())
+		formatEMBLHelper(t, &cpy, in)
+
+	default:
+		t.Errorf("result.Value.(type) = %T, want %T", seq, EMBL{})
+	}
+}
+
+func TestEMBLGenBankConversion(t *testing.T) {
+	gb, err := parseString(GenBankParser, testutils.ReadTestfile(t, "NC_001422.gb"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	emb, err := parseString(EMBLParser, testutils.ReadTestfile(t, "NC_001422.embl"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	info := gb.Info().(GenBankFields)
+	info.Extra = nil
+
+	testutils.Equals(t, emb.Info(), info)
+	testutils.Equals(t, emb.Features(), gb.Features())
+	testutils.Equals(t, emb.Bytes(), gb.Bytes())
+}
+
+func TestEMBLParser(t *testing.T) {
+	in := testutils.ReadTestfile(t, "ena_sample.embl")
+	seq, err := parseString(EMBLParser, in)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	emb := seq.(EMBL)
+	info := emb.Fields
+
+	testutils.Equals(t, info.LocusName, "X56734")
+	testutils.Equals(t, info.Accession, "X56734 S46826")
+	testutils.Equals(t, info.Version, "X56734.1")
+	testutils.Equals(t, info.Molecule, gts.RNA)
+	testutils.Equals(t, info.Topology, gts.Linear)
+	testutils.Equals(t, info.Division, "PLN")
+	testutils.Equals(t, info.Date, Date{2005, time.November, 25})
+	testutils.Equals(t, info.Source.Name, "Trifolium repens")
+	testutils.Equals(t, len(info.Source.Taxon), 17)
+	testutils.Equals(t, info.DBLink.Get("MD5"), []string{"1e51ca3a5450c43524b9185c236cc5cc"})
+	testutils.Equals(t, info.Comments, []string{"This is a sample comment."})
+
+	if len(info.References) != 2 {
+		t.Errorf("len(info.References) = %d, want 2", len(info.References))
+		return
+	}
+
+	ref := info.References[0]
+	testutils.Equals(t, ref.Number, 5)
+	testutils.Equals(t, ref.Info, "(bases 1 to 120)")
+	testutils.Equals(t, ref.Xref, map[string]string{"DOI": "10.1007/BF00039495", "PUBMED": "1907511"})
+	testutils.Equals(t, ref.Authors, "Oxtoby E., Dunn M.A., Pancoro A., Hughes M.A.")
+	testutils.Equals(t, ref.Title, "Nucleotide and derived amino acid sequence of the cyanogenic\nbeta-glucosidase (linamarase) from white clover (Trifolium repens L.)")
+
+	ref = info.References[1]
+	testutils.Equals(t, ref.Title, "")
+	testutils.Equals(t, ref.Journal, "Submitted (19-NOV-1990) to the INSDC.\nHughes M.A., University of Newcastle Upon Tyne, Medical School,\nNewcastle Upon Tyne, NE2 4HH, UK")
+
+	if len(emb.Table) != 2 {
+		t.Errorf("len(emb.Table) = %d, want 2", len(emb.Table))
+		return
+	}
+	testutils.Equals(t, emb.Table[1].Loc.String(), "join(1..10,20..30,40..50,60..70,80..90,100..120)")
+	testutils.Equals(t, emb.Table[1].Props.Get("note"), []string{"non-functional"})
+
+	testutils.Equals(t, len(emb.Data), 120)
+}
+
+var emblIOFailTests = []string{
+	"",
+	"LOCUS       NC_001422               5386 bp ss-DNA     circular PHG 06-JUL-2018",
+	"ID   X56734; SV 1; linear; mRNA; STD; PLN.",
+	"ID   X56734; SV 1; topology; mRNA; STD; PLN; 120 BP.",
+	"ID   X56734; SV 1; linear; mRNA; STD; PLN; foo BP.",
+	"ID   X56734; SV 1; linear; mRNA; STD; PLN; 120 BP.\nXX\n",
+	"" +
+		"ID   X56734; SV 1; linear; mRNA; STD; PLN; 12 BP.\n" +
+		"SQ   Sequence 10 BP; 3 A; 2 C; 2 G; 3 T; 0 other;\n" +
+		"     aaacaaacca                                                        10\n" +
+		"//\n",
+	"" +
+		"ID   X56734; SV 1; linear; mRNA; STD; PLN; 10 BP.\n" +
+		"RA   Hughes M.A.;\n" +
+		"//\n",
+	"" +
+		"ID   X56734; SV 1; linear; mRNA; STD; PLN; 10 BP.\n" +
+		"RN   \n" +
+		"//\n",
+	"" +
+		"ID   X56734; SV 1; linear; mRNA; STD; PLN; 10 BP.\n" +
+		"RN   [x]\n" +
+		"//\n",
+	"" +
+		"ID   X56734; SV 1; linear; mRNA; STD; PLN; 10 BP.\n" +
+		"FT   source          1..10\n" +
+		"FT   foo\n" +
+		"//\n",
+	"" +
+		"ID   X56734; SV 1; linear; mRNA; STD; PLN; 10 BP.\n" +
+		"SQ   Sequence 10 BP; 3 A; 2 C; 2 G; 3 T; 0 other;\n" +
+		"     aaacaaacca                                                        10\n",
+}
+
+func TestEMBLIOFail(t *testing.T) {
+	parser := pars.AsParser(EMBLParser)
+	for _, in := range emblIOFailTests {
+		state := pars.FromString(in)
+		if err := parser(state, pars.Void); err == nil {
+			t.Errorf("while parsing`\n%s\n`: expected error", in)
+		}
+	}
+
+	w := EMBLWriter{&strings.Builder{}}
+	n, err := w.WriteSeq(gts.New(nil, nil, nil))
+	if n != 0 || err == nil {
+		t.Errorf("w.WriteSeq(nil) = (%d, %v), want (0, error)", n, err)
+	}
+}
@@ -0,0 +1,264 @@
+package seqio
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// FaidxEntry represents a single record of a samtools compatible FASTA index.
+// Name is the first word of the description line, Length is the length of the
+// sequence, and Offset is the byte offset of the first base of the sequence.
+// LineBases and LineWidth are the number of bases and bytes in each line of
+// the sequence respectively.
+type FaidxEntry struct {
+	Name      string
+	Length    int
+	Offset    int64
+	LineBases int
+	LineWidth int
+}
+
+// position returns the byte offset of the base at the given position.
+func (e FaidxEntry) position(pos int) int64 {
+	if e.LineBases == 0 {
+		return e.Offset
+	}
+	lines, rem := pos/e.LineBases, pos%e.LineBases
+	return e.Offset + int64(lines)*int64(e.LineWidth) + int64(rem)
+}
+
+// ReadRegion reads the bases in the region [start, end) of the sequence by
+// seeking directly to the region in the file.
+func (e FaidxEntry) ReadRegion(r io.ReaderAt, start, end int) ([]byte, error) {
+	if start < 0 || end < start || e.Length < end {
+		return nil, fmt.Errorf("region %d..%d is out of range for %q of length %d", start+1, end, e.Name, e.Length)
+	}
+	if start == end {
+		return []byte{}, nil
+	}
+
+	head, tail := e.position(start), e.position(end-1)+1
+	p := make([]byte, tail-head)
+	if _, err := r.ReadAt(p, head); err != nil {
+		return nil, err
+	}
+
+	q := p[:0]
+	for _, c := range p {
+		if c != '\n' && c != '\r' {
+			q = append(q, c)
+		}
+	}
+
+	if len(q) != end-start {
+		return nil, fmt.Errorf("region %d..%d of %q does not match the index", start+1, end, e.Name)
+	}
+
+	return q, nil
+}
+
+// ReadDesc reads the description line of the sequence.
+func (e FaidxEntry) ReadDesc(r io.ReaderAt) (string, error) {
+	buf := []byte{}
+	for head := e.Offset - 1; head > 0; {
+		n := int64(4096)
+		if head < n {
+			n = head
+		}
+		p := make([]byte, n)
+		if _, err := r.ReadAt(p, head-n); err != nil {
+			return "", err
+		}
+		head -= n
+		if i := bytes.LastIndexByte(p, '\n'); i >= 0 {
+			buf = append(p[i+1:], buf...)
+			break
+		}
+		buf = append(p, buf...)
+	}
+
+	line := bytes.TrimRight(buf, "\r")
+	if len(line) == 0 || line[0] != '>' {
+		return "", fmt.Errorf("expected a FASTA description line for %q", e.Name)
+	}
+
+	return string(line[1:]), nil
+}
+
+// FaidxIndex represents a samtools compatible FASTA index.
+type FaidxIndex []FaidxEntry
+
+// Lookup the entry with the given name.
+func (idx FaidxIndex) Lookup(name string) (FaidxEntry, bool) {
+	for _, e := range idx {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return FaidxEntry{}, false
+}
+
+// Fetch reads the given region of a sequence using the index. The region is
+// given in the samtools format, `name`, `name:start`, or `name:start-end`,
+// where the start and end positions are one-based and inclusive. The
+// description of the returned sequence will be the region string.
+func (idx FaidxIndex) Fetch(r io.ReaderAt, region string) (Fasta, error) {
+	name, start, end := region, 0, -1
+	e, ok := idx.Lookup(region)
+	if !ok {
+		var err error
+		if name, start, end, err = ParseFaidxRegion(region); err != nil {
+			return Fasta{}, err
+		}
+		if e, ok = idx.Lookup(name); !ok {
+			return Fasta{}, fmt.Errorf("sequence %q not found in index", name)
+		}
+	}
+
+	if end < 0 || e.Length < end {
+		end = e.Length
+	}
+
+	p, err := e.ReadRegion(r, start, end)
+	if err != nil {
+		return Fasta{}, err
+	}
+
+	return Fasta{region, p}, nil
+}
+
+// WriteTo satisfies the io.WriterTo interface.
+func (idx FaidxIndex) WriteTo(w io.Writer) (int64, error) {
+	b := strings.Builder{}
+	for _, e := range idx {
+		b.WriteString(fmt.Sprintf("%s\t%d\t%d\t%d\t%d\n", e.Name, e.Length, e.Offset, e.LineBases, e.LineWidth))
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ParseFaidxRegion parses a samtools format region string. The start and end
+// positions are returned as a zero-based half-open interval, and the end will
+// be -1 if the region extends to the end of the sequence.
+func ParseFaidxRegion(s string) (string, int, int, error) {
+	i := strings.LastIndexByte(s, ':')
+	if i < 0 {
+		return s, 0, -1, nil
+	}
+
+	name, span := s[:i], strings.ReplaceAll(s[i+1:], ",", "")
+	if name == "" {
+		return "", 0, 0, fmt.Errorf("missing sequence name in region %q", s)
+	}
+
+	lower, upper := span, ""
+	if j := strings.IndexByte(span, '-'); j >= 0 {
+		lower, upper = span[:j], span[j+1:]
+	}
+
+	start, err := strconv.Atoi(lower)
+	if err != nil || start < 1 {
+		return "", 0, 0, fmt.Errorf("invalid start position in region %q", s)
+	}
+
+	end := -1
+	if upper != "" {
+		if end, err = strconv.Atoi(upper); err != nil || end < start {
+			return "", 0, 0, fmt.Errorf("invalid end position in region %q", s)
+		}
+	}
+
+	return name, start - 1, end, nil
+}
+
+// ReadFaidx reads a samtools compatible FASTA index.
+func ReadFaidx(r io.Reader) (FaidxIndex, error) {
+	idx := FaidxIndex{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("expected 5 columns at line %d, got %d", line, len(fields))
+		}
+
+		values := make([]int64, 4)
+		for i, field := range fields[1:] {
+			n, err := strconv.ParseInt(field, 10, 64)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid value %q in column %d at line %d", field, i+2, line)
+			}
+			values[i] = n
+		}
+
+		e := FaidxEntry{fields[0], int(values[0]), values[1], int(values[2]), int(values[3])}
+		if e.LineWidth < e.LineBases {
+			return nil, fmt.Errorf("line width is smaller than line bases at line %d", line)
+		}
+		idx = append(idx, e)
+	}
+	return idx, scanner.Err()
+}
+
+// BuildFaidx reads a FASTA file to build a samtools compatible FASTA index.
+// All lines of a sequence except the last must have the same length.
+func BuildFaidx(r io.Reader) (FaidxIndex, error) {
+	br := bufio.NewReader(r)
+	if isCompressed(br) {
+		return nil, ErrCompressedIndex
+	}
+	idx := FaidxIndex{}
+
+	offset := int64(0)
+	var e *FaidxEntry
+	short := false
+
+	for lineno := 1; ; lineno++ {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			offset += int64(len(line))
+			bases := len(bytes.TrimRight(line, "\r\n"))
+
+			switch {
+			case line[0] == '>':
+				fields := strings.Fields(string(line[1:]))
+				if len(fields) == 0 {
+					return nil, fmt.Errorf("missing sequence name at line %d", lineno)
+				}
+				idx = append(idx, FaidxEntry{Name: fields[0], Offset: offset})
+				e, short = &idx[len(idx)-1], false
+
+			case e == nil:
+				if bases > 0 {
+					return nil, fmt.Errorf("expected a FASTA description line at line %d", lineno)
+				}
+
+			case bases == 0:
+				short = true
+
+			default:
+				if e.LineBases == 0 {
+					e.LineBases, e.LineWidth = bases, len(line)
+				} else if short || bases > e.LineBases || (bases == e.LineBases && len(line) != e.LineWidth && err != io.EOF) {
+					return nil, fmt.Errorf("different line length in sequence %q at line %d", e.Name, lineno)
+				}
+				short = short || bases < e.LineBases
+				e.Length += bases
+			}
+		}
+
+		if err == io.EOF {
+			if len(idx) == 0 {
+				return nil, errors.New("no FASTA records found")
+			}
+			return idx, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
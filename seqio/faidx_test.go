@@ -0,0 +1,160 @@
+package seqio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-gts/gts/internal/testutils"
+)
+
+func readFaidxTestfile(t *testing.T) string {
+	t.Helper()
+	return testutils.ReadTestfile(t, "NC_001422.fasta") + testutils.ReadTestfile(t, "NC_001422_part.fasta")
+}
+
+func TestFaidx(t *testing.T) {
+	in := readFaidxTestfile(t)
+	exp := FaidxIndex{
+		{"NC_001422.1", 5386, 49, 70, 71},
+		{"NC_001422.1:2380-2512", 133, 5571, 70, 71},
+	}
+
+	idx, err := BuildFaidx(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("BuildFaidx: %v", err)
+	}
+	testutils.Equals(t, idx, exp)
+
+	buf := bytes.Buffer{}
+	if _, err := idx.WriteTo(&buf); err != nil {
+		t.Errorf("idx.WriteTo: %v", err)
+	}
+	out := "NC_001422.1\t5386\t49\t70\t71\nNC_001422.1:2380-2512\t133\t5571\t70\t71\n"
+	testutils.Equals(t, buf.String(), out)
+
+	idx, err = ReadFaidx(&buf)
+	if err != nil {
+		t.Fatalf("ReadFaidx: %v", err)
+	}
+	testutils.Equals(t, idx, exp)
+
+	seqs := []Fasta{}
+	scanner := NewScanner(FastaParser, strings.NewReader(in))
+	for scanner.Scan() {
+		seqs = append(seqs, scanner.Value().(Fasta))
+	}
+
+	r := strings.NewReader(in)
+	for i, e := range idx {
+		desc, err := e.ReadDesc(r)
+		if err != nil || desc != seqs[i].Desc {
+			t.Errorf("e.ReadDesc(r) = %q, %v, want %q", desc, err, seqs[i].Desc)
+		}
+
+		for _, span := range [][2]int{{0, e.Length}, {0, 1}, {69, 71}, {70, 70}, {e.Length - 1, e.Length}, {10, 100}} {
+			p, err := e.ReadRegion(r, span[0], span[1])
+			q := seqs[i].Data[span[0]:span[1]]
+			if err != nil || !bytes.Equal(p, q) {
+				t.Errorf("e.ReadRegion(r, %d, %d) = %q, %v, want %q", span[0], span[1], p, err, q)
+			}
+		}
+
+		for _, span := range [][2]int{{-1, 1}, {2, 1}, {0, e.Length + 1}} {
+			if _, err := e.ReadRegion(r, span[0], span[1]); err == nil {
+				t.Errorf("expected error in e.ReadRegion(r, %d, %d)", span[0], span[1])
+			}
+		}
+	}
+}
+
+var faidxFetchTests = []struct {
+	region     string
+	seq        int
+	start, end int
+}{
+	{"NC_001422.1", 0, 0, 5386},
+	{"NC_001422.1:1-10", 0, 0, 10},
+	{"NC_001422.1:5,001-5,100", 0, 5000, 5100},
+	{"NC_001422.1:5300", 0, 5299, 5386},
+	{"NC_001422.1:5300-6000", 0, 5299, 5386},
+	{"NC_001422.1:2380-2512", 1, 0, 133},
+	{"NC_001422.1:2380-2512:11-20", 1, 10, 20},
+}
+
+func TestFaidxFetch(t *testing.T) {
+	in := readFaidxTestfile(t)
+	idx, err := BuildFaidx(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("BuildFaidx: %v", err)
+	}
+
+	seqs := []Fasta{}
+	scanner := NewScanner(FastaParser, strings.NewReader(in))
+	for scanner.Scan() {
+		seqs = append(seqs, scanner.Value().(Fasta))
+	}
+
+	r := strings.NewReader(in)
+	for _, tt := range faidxFetchTests {
+		out, err := idx.Fetch(r, tt.region)
+		if err != nil {
+			t.Errorf("idx.Fetch(r, %q): %v", tt.region, err)
+			continue
+		}
+		exp := Fasta{tt.region, seqs[tt.seq].Data[tt.start:tt.end]}
+		testutils.Equals(t, out, exp)
+	}
+
+	for _, region := range []string{"foo", "NC_001422.1:0-10", "NC_001422.1:20-10", "NC_001422.1:a-10", ":1-10"} {
+		if _, err := idx.Fetch(r, region); err == nil {
+			t.Errorf("expected error in idx.Fetch(r, %q)", region)
+		}
+	}
+}
+
+func TestFaidxFail(t *testing.T) {
+	buildTests := []string{
+		"",
+		"ACGT\n",
+		">\nACGT\n",
+		">foo\nACGT\nACGTA\n",
+		">foo\nACGT\nAC\nAC\n",
+		">foo\nACGT\n\nACGT\n",
+		">foo\nACGT\r\nACGT\nACGT\n",
+	}
+	for _, in := range buildTests {
+		if _, err := BuildFaidx(strings.NewReader(in)); err == nil {
+			t.Errorf("expected error in BuildFaidx(%q)", in)
+		}
+	}
+
+	readTests := []string{
+		"foo\t1\t2\t3\n",
+		"foo\t1\t2\t3\tbar\n",
+		"foo\t1\t2\t3\t-4\n",
+		"foo\t1\t2\t3\t2\n",
+	}
+	for _, in := range readTests {
+		if _, err := ReadFaidx(strings.NewReader(in)); err == nil {
+			t.Errorf("expected error in ReadFaidx(%q)", in)
+		}
+	}
+}
+
+func TestFaidxTrailingLine(t *testing.T) {
+	in := ">foo bar\nACGT\nACGT\n\n>baz\nAC\n"
+	idx, err := BuildFaidx(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("BuildFaidx: %v", err)
+	}
+	exp := FaidxIndex{{"foo", 8, 9, 4, 5}, {"baz", 2, 25, 2, 3}}
+	testutils.Equals(t, idx, exp)
+
+	in = ">foo\nACGT\nACGT"
+	idx, err = BuildFaidx(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("BuildFaidx: %v", err)
+	}
+	testutils.Equals(t, idx, FaidxIndex{{"foo", 8, 5, 4, 5}})
+}
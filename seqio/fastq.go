@@ -0,0 +1,212 @@
+package seqio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-gts/gts"
+	"github.com/go-pars/pars"
+)
+
+// UnknownQuality is the quality score assigned to bases whose quality is not
+// known, such as bases inserted into a FASTQ sequence.
+const UnknownQuality = '!'
+
+func fitQuality(q []byte, n int) []byte {
+	if len(q) >= n {
+		return q[:n]
+	}
+	return append(q, bytes.Repeat([]byte{UnknownQuality}, n-len(q))...)
+}
+
+// FastqFields represents the metadata of a FASTQ record. The quality scores
+// are kept in the metadata so that they are kept aligned with the sequence
+// through sequence manipulations.
+type FastqFields struct {
+	Desc    string
+	Quality []byte
+}
+
+// String satisfies the fmt.Stringer interface.
+func (fqf FastqFields) String() string {
+	return fqf.Desc
+}
+
+// Shift returns a metadata with qualities inserted at the given position.
+func (fqf FastqFields) Shift(i, n int) interface{} {
+	return fqf.Expand(i, n)
+}
+
+// Expand returns a metadata with qualities inserted or deleted at the given
+// position.
+func (fqf FastqFields) Expand(i, n int) interface{} {
+	q := fitQuality(append([]byte(nil), fqf.Quality...), gts.Max(len(fqf.Quality), i))
+	switch {
+	case n > 0:
+		p := make([]byte, 0, len(q)+n)
+		p = append(p, q[:i]...)
+		p = append(p, bytes.Repeat([]byte{UnknownQuality}, n)...)
+		p = append(p, q[i:]...)
+		fqf.Quality = p
+	case n < 0:
+		j := gts.Min(i-n, len(q))
+		fqf.Quality = append(q[:i], q[j:]...)
+	default:
+		fqf.Quality = q
+	}
+	return fqf
+}
+
+// Slice returns a metadata with the qualities sliced with the given region.
+func (fqf FastqFields) Slice(start, end int) interface{} {
+	q := fitQuality(append([]byte(nil), fqf.Quality...), gts.Max(len(fqf.Quality), end))
+	fqf.Quality = q[start:end]
+	return fqf
+}
+
+// Reverse returns a metadata with the qualities in the reversed order.
+func (fqf FastqFields) Reverse(length int) interface{} {
+	q := fitQuality(append([]byte(nil), fqf.Quality...), length)
+	for i, j := 0, len(q)-1; i < j; i, j = i+1, j-1 {
+		q[i], q[j] = q[j], q[i]
+	}
+	fqf.Quality = q
+	return fqf
+}
+
+// Rotate returns a metadata with the qualities rotated by the given amount.
+func (fqf FastqFields) Rotate(n int) interface{} {
+	q := fqf.Quality
+	m := len(q) - n
+	if m < 0 || m > len(q) {
+		return fqf
+	}
+	p := make([]byte, 0, len(q))
+	p = append(p, q[m:]...)
+	p = append(p, q[:m]...)
+	fqf.Quality = p
+	return fqf
+}
+
+// Fastq represents a FASTQ format sequence object.
+type Fastq struct {
+	Fields FastqFields
+	Data   []byte
+}
+
+// Info returns the metadata of the sequence.
+func (fq Fastq) Info() interface{} {
+	return fq.Fields
+}
+
+// Features returns the feature table of the sequence.
+func (fq Fastq) Features() gts.FeatureSlice {
+	return nil
+}
+
+// Bytes returns the byte representation of the sequence.
+func (fq Fastq) Bytes() []byte {
+	return fq.Data
+}
+
+// WithInfo creates a shallow copy of the given Sequence object and swaps the
+// metadata with the given value.
+func (fq Fastq) WithInfo(info interface{}) gts.Sequence {
+	switch v := info.(type) {
+	case FastqFields:
+		return Fastq{v, fq.Data}
+	default:
+		return gts.New(v, fq.Features(), fq.Bytes())
+	}
+}
+
+// WithFeatures creates a shallow copy of the given Sequence object and swaps
+// the feature table with the given features.
+func (fq Fastq) WithFeatures(ff []gts.Feature) gts.Sequence {
+	if len(ff) == 0 {
+		return fq
+	}
+	return gts.New(fq.Info(), ff, fq.Bytes())
+}
+
+// WithBytes creates a shallow copy of the given Sequence object and swaps the
+// byte representation with the given byte slice.
+func (fq Fastq) WithBytes(p []byte) gts.Sequence {
+	return Fastq{fq.Fields, p}
+}
+
+// WriteTo satisfies the io.WriterTo interface.
+func (fq Fastq) WriteTo(w io.Writer) (int64, error) {
+	desc := strings.ReplaceAll(fq.Fields.Desc, "\n", " ")
+	qual := fitQuality(fq.Fields.Quality, len(fq.Data))
+	s := fmt.Sprintf("@%s\n%s\n+\n%s\n", desc, fq.Data, qual)
+	n, err := io.WriteString(w, s)
+	return int64(n), err
+}
+
+// FastqWriter writes a gts.Sequence to an io.Writer in FASTQ format.
+type FastqWriter struct {
+	w io.Writer
+}
+
+// WriteSeq satisfies the seqio.SeqWriter interface.
+func (w FastqWriter) WriteSeq(seq gts.Sequence) (int, error) {
+	switch v := seq.(type) {
+	case Fastq:
+		n, err := v.WriteTo(w.w)
+		return int(n), err
+	case *Fastq:
+		return w.WriteSeq(*v)
+	default:
+		switch info := v.Info().(type) {
+		case FastqFields:
+			fq := Fastq{info, v.Bytes()}
+			return w.WriteSeq(fq)
+		default:
+			return 0, fmt.Errorf("gts does not know how to format a sequence with metadata type `%T` as FASTQ", info)
+		}
+	}
+}
+
+// FastqParser attempts to parse a single FASTQ file entry.
+func FastqParser(state *pars.State, result *pars.Result) error {
+	if err := pars.Byte('@')(state, result); err != nil {
+		return err
+	}
+	pars.Line(state, result)
+	desc := string(result.Token)
+
+	data := []byte{}
+	for {
+		if err := state.Request(1); err != nil {
+			return pars.NewError("expected `+` separator line", state.Position())
+		}
+		if state.Buffer()[0] == '+' {
+			pars.Line(state, result)
+			break
+		}
+		pars.Line(state, result)
+		data = append(data, result.Token...)
+	}
+
+	qual := []byte{}
+	for len(qual) < len(data) {
+		if err := state.Request(1); err != nil {
+			what := fmt.Sprintf("expected %d quality scores, got %d", len(data), len(qual))
+			return pars.NewError(what, state.Position())
+		}
+		pars.Line(state, result)
+		qual = append(qual, result.Token...)
+	}
+
+	if len(qual) != len(data) {
+		what := fmt.Sprintf("expected %d quality scores, got %d", len(data), len(qual))
+		return pars.NewError(what, state.Position())
+	}
+
+	state.Clear()
+	result.SetValue(Fastq{FastqFields{desc, qual}, data})
+	return nil
+}
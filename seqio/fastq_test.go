@@ -0,0 +1,98 @@
+package seqio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/internal/testutils"
+	"github.com/go-pars/pars"
+)
+
+func TestFastqIO(t *testing.T) {
+	in := testutils.ReadTestfile(t, "sample.fastq")
+	scanner := NewScanner(FastqParser, strings.NewReader(in))
+
+	b := strings.Builder{}
+	w := FastqWriter{&b}
+
+	n := 0
+	for scanner.Scan() {
+		seq := scanner.Value()
+		switch v := seq.(type) {
+		case Fastq:
+			if len(v.Fields.Quality) != gts.Len(v) {
+				t.Errorf("len(v.Fields.Quality) = %d, want %d", len(v.Fields.Quality), gts.Len(v))
+			}
+			if _, err := w.WriteSeq(&v); err != nil {
+				t.Errorf("w.WriteSeq(seq): %v", err)
+			}
+		default:
+			t.Errorf("scanner.Value().(type) = %T, want %T", seq, Fastq{})
+		}
+		n++
+	}
+	if err := scanner.Err(); err != nil {
+		t.Errorf("scanner.Err(): %v", err)
+	}
+	if n != 2 {
+		t.Errorf("scanned %d sequences, want 2", n)
+	}
+
+	testutils.DiffLine(t, in, b.String())
+}
+
+func TestFastqManipulation(t *testing.T) {
+	info := FastqFields{"read", []byte("ABCDEFGH")}
+	in := Fastq{info, []byte("aattggcc")}
+
+	quality := func(seq gts.Sequence) string {
+		return string(seq.Info().(FastqFields).Quality)
+	}
+
+	testutils.Equals(t, quality(gts.Slice(in, 2, 6)), "CDEF")
+	testutils.Equals(t, quality(gts.Slice(in, 6, 2)), "GHAB")
+	testutils.Equals(t, quality(gts.Reverse(in)), "HGFEDCBA")
+	testutils.Equals(t, quality(gts.Rotate(in, 2)), "GHABCDEF")
+	testutils.Equals(t, quality(gts.Delete(in, 2, 3)), "ABFGH")
+
+	guest := gts.New(nil, nil, []byte("tt"))
+	testutils.Equals(t, quality(gts.Insert(in, 4, guest)), "ABCD!!EFGH")
+	testutils.Equals(t, quality(gts.Embed(in, 4, guest)), "ABCD!!EFGH")
+
+	out := gts.Reverse(gts.Slice(in, 1, 5))
+	testutils.Equals(t, string(out.Bytes()), "gtta")
+	testutils.Equals(t, quality(out), "EDCB")
+}
+
+func TestFastqWriterPadding(t *testing.T) {
+	b := strings.Builder{}
+	seq := gts.New(FastqFields{"read", []byte("AB")}, nil, []byte("atgc"))
+	if _, err := (FastqWriter{&b}).WriteSeq(seq); err != nil {
+		t.Errorf("w.WriteSeq(seq): %v", err)
+	}
+	testutils.Equals(t, b.String(), "@read\natgc\n+\nAB!!\n")
+}
+
+var fastqIOFailTests = []string{
+	"@read\natgc\n",
+	"@read\natgc\n+\nAB\n",
+	"@read\natgc\n+\nABCDE\n",
+	">read\natgc\n",
+}
+
+func TestFastqIOFail(t *testing.T) {
+	parser := pars.AsParser(FastqParser)
+	for _, in := range fastqIOFailTests {
+		state := pars.FromString(in)
+		if err := parser(state, pars.Void); err == nil {
+			t.Errorf("while parsing`\n%s\n`: expected error", in)
+		}
+	}
+
+	w := FastqWriter{&strings.Builder{}}
+	n, err := w.WriteSeq(gts.New(nil, nil, nil))
+	if n != 0 || err == nil {
+		t.Errorf("w.WriteSeq(nil) = (%d, %v), want (0, error)", n, err)
+	}
+}
@@ -12,11 +12,16 @@ const (
 	FastqFile
 	GenBankFile
 	EMBLFile
+	GFF3File
+	BEDFile
+	JSONFile
+	JSONLinesFile
 )
 
 // Detect returns the FileType associated to extension of the given filename.
+// Compression extensions such as `.gz` are ignored.
 func Detect(filename string) FileType {
-	ext := filepath.Ext(filename)
+	ext := filepath.Ext(trimCompression(filename))
 	if ext != "" {
 		ext = ext[1:]
 	}
@@ -34,6 +39,14 @@ func ToFileType(name string) FileType {
 		return GenBankFile
 	case "emb", "embl":
 		return EMBLFile
+	case "gff", "gff3":
+		return GFF3File
+	case "bed":
+		return BEDFile
+	case "json":
+		return JSONFile
+	case "jsonl", "ndjson":
+		return JSONLinesFile
 	default:
 		return DefaultFile
 	}
@@ -13,6 +13,16 @@ var detectTests = []struct {
 	{"foo.genbank", GenBankFile},
 	{"foo.emb", EMBLFile},
 	{"foo.embl", EMBLFile},
+	{"foo.gff", GFF3File},
+	{"foo.gff3", GFF3File},
+	{"foo.bed", BEDFile},
+	{"foo.json", JSONFile},
+	{"foo.jsonl", JSONLinesFile},
+	{"foo.ndjson", JSONLinesFile},
+	{"foo.gb.gz", GenBankFile},
+	{"foo.fasta.bgz", FastaFile},
+	{"foo.embl.zst", EMBLFile},
+	{"foo.gz", DefaultFile},
 }
 
 func TestDetect(t *testing.T) {
@@ -185,6 +185,11 @@ func (gb GenBank) WithBytes(p []byte) gts.Sequence {
 	return GenBank{gb.Fields, gb.Table, NewOrigin(p)}
 }
 
+// Topology returns the topology of the sequence.
+func (gb GenBank) Topology() gts.Topology {
+	return gb.Fields.Topology
+}
+
 // WithTopology creates a shallow copy of the given Sequence object and swaps
 // the topology value with the given value.
 func (gb GenBank) WithTopology(t gts.Topology) gts.Sequence {
@@ -93,6 +93,9 @@ func TestGenBankWithInterface(t *testing.T) {
 	if top != gts.Circular {
 		t.Errorf("topology is %q, expected %q", top, gts.Circular)
 	}
+	if top := gts.TopologyOf(out); top != gts.Circular {
+		t.Errorf("gts.TopologyOf(out) = %q, expected %q", top, gts.Circular)
+	}
 }
 
 func TestGenBankSlice(t *testing.T) {
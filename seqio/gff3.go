@@ -0,0 +1,468 @@
+package seqio
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-gts/gts"
+	"github.com/go-pars/pars"
+)
+
+const gff3Header = "##gff-version 3"
+
+// GFF3 attribute names with reserved meanings, mapped to their INSDC qualifier
+// counterparts where one exists.
+var gff3QualifierNames = map[string]string{
+	"Dbxref": "db_xref",
+	"Note":   "note",
+}
+
+var gff3AttributeNames = map[string]string{
+	"db_xref": "Dbxref",
+	"note":    "Note",
+}
+
+var gff3RNAKeys = []string{
+	"mRNA", "tRNA", "rRNA", "ncRNA", "tmRNA", "misc_RNA", "precursor_RNA",
+}
+
+func isGFF3RNAKey(key string) bool {
+	for _, name := range gff3RNAKeys {
+		if key == name {
+			return true
+		}
+	}
+	return false
+}
+
+func gff3Escape(s, special string) string {
+	b := strings.Builder{}
+	for _, c := range []byte(s) {
+		if c < 0x20 || c == 0x7f || c == '%' || strings.IndexByte(special, c) >= 0 {
+			b.WriteString(fmt.Sprintf("%%%02X", c))
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func gff3Unescape(s string) string {
+	if u, err := url.PathUnescape(s); err == nil {
+		return u
+	}
+	return s
+}
+
+type gff3Segment struct {
+	Start   int
+	End     int
+	Strand  byte
+	Partial gts.Partial
+}
+
+func flattenGFF3(loc gts.Location, strand byte) []gff3Segment {
+	switch v := loc.(type) {
+	case gts.Between:
+		pos := gts.Max(int(v), 1)
+		return []gff3Segment{{pos, pos, strand, gts.Complete}}
+	case gts.Point:
+		return []gff3Segment{{int(v) + 1, int(v) + 1, strand, gts.Complete}}
+	case gts.Ranged:
+		return []gff3Segment{{v.Start + 1, v.End, strand, v.Partial}}
+	case gts.Ambiguous:
+		return []gff3Segment{{v.Start + 1, v.End, strand, gts.Complete}}
+	case gts.Joined:
+		return flattenGFF3List(v, strand)
+	case gts.Ordered:
+		return flattenGFF3List(v, strand)
+	case gts.Complemented:
+		switch strand {
+		case '-':
+			return flattenGFF3(v.Location, '+')
+		default:
+			return flattenGFF3(v.Location, '-')
+		}
+	default:
+		return nil
+	}
+}
+
+func flattenGFF3List(locs []gts.Location, strand byte) []gff3Segment {
+	segs := []gff3Segment{}
+	for _, loc := range locs {
+		segs = append(segs, flattenGFF3(loc, strand)...)
+	}
+	return segs
+}
+
+func gff3Phases(segs []gff3Segment, codonStart int) []int {
+	order := make([]int, len(segs))
+	for i := range order {
+		order[i] = i
+	}
+
+	reverse := true
+	for _, seg := range segs {
+		if seg.Strand != '-' {
+			reverse = false
+		}
+	}
+	if reverse {
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+
+	phases := make([]int, len(segs))
+	consumed := 1 - codonStart
+	for _, i := range order {
+		phases[i] = ((-consumed)%3 + 3) % 3
+		consumed += segs[i].End - segs[i].Start + 1
+	}
+	return phases
+}
+
+func gff3Tag(f gts.Feature) string {
+	if values := f.Props.Get("locus_tag"); len(values) > 0 {
+		return values[0]
+	}
+	if values := f.Props.Get("gene"); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func formatGFF3Attributes(f gts.Feature, id, parent string, seg gff3Segment, circular bool) string {
+	attrs := []string{}
+	if id != "" {
+		attrs = append(attrs, "ID="+gff3Escape(id, ";=&,"))
+	}
+	if parent != "" {
+		attrs = append(attrs, "Parent="+gff3Escape(parent, ";=&,"))
+	}
+	if circular {
+		attrs = append(attrs, "Is_circular=true")
+	}
+	if seg.Partial.Partial5 {
+		attrs = append(attrs, fmt.Sprintf("start_range=.,%d", seg.Start))
+	}
+	if seg.Partial.Partial3 {
+		attrs = append(attrs, fmt.Sprintf("end_range=%d,.", seg.End))
+	}
+	for _, key := range f.Props.Keys() {
+		values := f.Props.Get(key)
+		escaped := make([]string, len(values))
+		for i, value := range values {
+			if value == "" && IsToggleQualifier(key) {
+				value = "true"
+			}
+			escaped[i] = gff3Escape(value, ";=&,")
+		}
+		name := key
+		if attr, ok := gff3AttributeNames[key]; ok {
+			name = attr
+		}
+		name = gff3Escape(name, ";=&,")
+		attrs = append(attrs, name+"="+strings.Join(escaped, ","))
+	}
+	if len(attrs) == 0 {
+		return "."
+	}
+	return strings.Join(attrs, ";")
+}
+
+// GFF3Writer writes the features of a gts.Sequence to an io.Writer in GFF3
+// format. The sequence itself is not written.
+type GFF3Writer struct {
+	w      io.Writer
+	header bool
+}
+
+// NewGFF3Writer creates a new GFF3Writer.
+func NewGFF3Writer(w io.Writer) *GFF3Writer {
+	return &GFF3Writer{w, false}
+}
+
+// WriteSeq satisfies the seqio.SeqWriter interface.
+func (w *GFF3Writer) WriteSeq(seq gts.Sequence) (int, error) {
+	b := strings.Builder{}
+
+	if !w.header {
+		b.WriteString(gff3Header + "\n")
+		w.header = true
+	}
+
+	seqid := SequenceID(seq)
+	if seqid == "" {
+		seqid = "."
+	}
+	seqid = gff3Escape(seqid, " ")
+
+	if gts.Len(seq) > 0 {
+		b.WriteString(fmt.Sprintf("##sequence-region %s 1 %d\n", seqid, gts.Len(seq)))
+	}
+
+	circular := false
+	if v, ok := seq.Info().(GenBankFields); ok {
+		circular = v.Topology == gts.Circular
+	}
+
+	ff := seq.Features()
+
+	ids := make([]string, len(ff))
+	counts := make(map[string]int)
+	genes := make(map[string]string)
+	rnas := make(map[string]string)
+	for i, f := range ff {
+		if f.Key == "source" {
+			continue
+		}
+		counts[f.Key]++
+		ids[i] = fmt.Sprintf("%s-%d", f.Key, counts[f.Key])
+		if tag := gff3Tag(f); tag != "" {
+			switch {
+			case f.Key == "gene":
+				if _, ok := genes[tag]; !ok {
+					genes[tag] = ids[i]
+				}
+			case isGFF3RNAKey(f.Key):
+				if _, ok := rnas[tag]; !ok {
+					rnas[tag] = ids[i]
+				}
+			}
+		}
+	}
+
+	for i, f := range ff {
+		parent := ""
+		if tag := gff3Tag(f); tag != "" && f.Key != "gene" && f.Key != "source" {
+			if id, ok := rnas[tag]; ok && !isGFF3RNAKey(f.Key) {
+				parent = id
+			} else if id, ok := genes[tag]; ok {
+				parent = id
+			}
+		}
+
+		key := f.Key
+		if key == "source" {
+			key = "region"
+		}
+
+		// Remote parts of a location cannot be represented in GFF3, and are
+		// noted in a comment line instead.
+		segs := flattenGFF3(f.Loc, '+')
+		if len(remoteAccessions(f.Loc)) > 0 {
+			b.WriteString(formatRemoteNote(seqid, f, len(segs) == 0))
+		}
+
+		phases := make([]int, len(segs))
+		if f.Key == "CDS" {
+			codonStart := 1
+			if values := f.Props.Get("codon_start"); len(values) > 0 {
+				if n, err := strconv.Atoi(values[0]); err == nil {
+					codonStart = n
+				}
+			}
+			phases = gff3Phases(segs, codonStart)
+		}
+
+		for j, seg := range segs {
+			phase := "."
+			if f.Key == "CDS" {
+				phase = strconv.Itoa(phases[j])
+			}
+			attrs := formatGFF3Attributes(f, ids[i], parent, seg, circular && f.Key == "source")
+			b.WriteString(fmt.Sprintf(
+				"%s\t.\t%s\t%d\t%d\t.\t%c\t%s\t%s\n",
+				seqid, gff3Escape(key, "\t"), seg.Start, seg.End, seg.Strand, phase, attrs,
+			))
+		}
+	}
+
+	return io.WriteString(w.w, b.String())
+}
+
+type gff3Row struct {
+	seqid string
+	key   string
+	loc   gts.Location
+	props gts.Props
+	id    string
+}
+
+func parseGFF3Row(line string, pos pars.Position) (gff3Row, error) {
+	cols := strings.Split(line, "\t")
+	if len(cols) != 9 {
+		what := fmt.Sprintf("expected 9 columns in GFF3 line, got %d", len(cols))
+		return gff3Row{}, pars.NewError(what, pos)
+	}
+
+	start, err := strconv.Atoi(cols[3])
+	if err != nil {
+		return gff3Row{}, pars.NewError("expected integer in start column", pos)
+	}
+	end, err := strconv.Atoi(cols[4])
+	if err != nil {
+		return gff3Row{}, pars.NewError("expected integer in end column", pos)
+	}
+	if start < 1 || end < start {
+		what := fmt.Sprintf("invalid feature range [%d, %d]", start, end)
+		return gff3Row{}, pars.NewError(what, pos)
+	}
+
+	key := gff3Unescape(cols[2])
+	if key == "region" {
+		key = "source"
+	}
+
+	row := gff3Row{seqid: gff3Unescape(cols[0]), key: key}
+	partial := gts.Complete
+
+	if cols[8] != "." && cols[8] != "" {
+		for _, attr := range strings.Split(cols[8], ";") {
+			if attr == "" {
+				continue
+			}
+			i := strings.IndexByte(attr, '=')
+			if i < 0 {
+				what := fmt.Sprintf("expected `=` in GFF3 attribute %q", attr)
+				return gff3Row{}, pars.NewError(what, pos)
+			}
+			name, value := gff3Unescape(attr[:i]), attr[i+1:]
+			switch name {
+			case "ID":
+				row.id = gff3Unescape(value)
+			case "Parent", "Is_circular":
+			case "start_range":
+				partial.Partial5 = true
+			case "end_range":
+				partial.Partial3 = true
+			default:
+				if qual, ok := gff3QualifierNames[name]; ok {
+					name = qual
+				}
+				for _, value := range strings.Split(value, ",") {
+					value = gff3Unescape(value)
+					if value == "true" && IsToggleQualifier(name) {
+						value = ""
+					}
+					row.props.Add(name, value)
+				}
+			}
+		}
+	}
+
+	switch {
+	case start == end && partial == gts.Complete:
+		row.loc = gts.Point(start - 1)
+	default:
+		row.loc = gts.PartialRange(start-1, end, partial)
+	}
+
+	if cols[6] == "-" {
+		row.loc = row.loc.Complement()
+	}
+
+	return row, nil
+}
+
+func mergeGFF3Rows(rows []gff3Row) gts.Feature {
+	head := rows[0]
+	if len(rows) == 1 {
+		return gts.NewFeature(head.key, head.loc, head.props)
+	}
+
+	reverse := true
+	for _, row := range rows {
+		if _, ok := row.loc.(gts.Complemented); !ok {
+			reverse = false
+		}
+	}
+
+	locs := make([]gts.Location, len(rows))
+	for i, row := range rows {
+		switch v := row.loc.(type) {
+		case gts.Complemented:
+			if reverse {
+				locs[i] = v.Location
+			} else {
+				locs[i] = v
+			}
+		default:
+			locs[i] = v
+		}
+	}
+
+	var loc gts.Location = gts.Joined(locs)
+	if reverse {
+		loc = loc.Complement()
+	}
+
+	return gts.NewFeature(head.key, loc, head.props)
+}
+
+// GFF3Parser attempts to parse the feature lines of a GFF3 file. The parsing
+// will stop at the end of the input or at a `##FASTA` directive.
+func GFF3Parser(state *pars.State, result *pars.Result) error {
+	if err := pars.String(gff3Header)(state, result); err != nil {
+		return err
+	}
+	pars.Line(state, result)
+
+	seqids := []string{}
+	groups := make(map[string][][]gff3Row)
+	index := make(map[[2]string]int)
+
+	for state.Request(1) == nil {
+		pos := state.Position()
+		pars.Line(state, result)
+		line := strings.TrimRight(string(result.Token), "\r")
+
+		switch {
+		case line == "##FASTA":
+			state.Clear()
+			result.SetValue(buildGFF3Table(seqids, groups))
+			return nil
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		}
+
+		row, err := parseGFF3Row(line, pos)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := groups[row.seqid]; !ok {
+			seqids = append(seqids, row.seqid)
+		}
+
+		key := [2]string{row.seqid, row.id}
+		if i, ok := index[key]; ok && row.id != "" {
+			groups[row.seqid][i] = append(groups[row.seqid][i], row)
+			continue
+		}
+
+		index[key] = len(groups[row.seqid])
+		groups[row.seqid] = append(groups[row.seqid], []gff3Row{row})
+	}
+
+	state.Clear()
+	result.SetValue(buildGFF3Table(seqids, groups))
+	return nil
+}
+
+func buildGFF3Table(seqids []string, groups map[string][][]gff3Row) FeatureTable {
+	table := make(FeatureTable, len(seqids))
+	for i, seqid := range seqids {
+		var ff gts.FeatureSlice
+		for _, rows := range groups[seqid] {
+			ff = append(ff, mergeGFF3Rows(rows))
+		}
+		table[i] = FeatureEntry{seqid, ff}
+	}
+	return table
+}
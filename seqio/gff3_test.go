@@ -0,0 +1,196 @@
+package seqio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/internal/testutils"
+	"github.com/go-pars/pars"
+)
+
+func TestGFF3IO(t *testing.T) {
+	seq, err := parseString(GenBankParser, testutils.ReadTestfile(t, "NC_001422.gb"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	b := strings.Builder{}
+	w := NewGFF3Writer(&b)
+	if _, err := w.WriteSeq(seq); err != nil {
+		t.Errorf("w.WriteSeq(seq): %v", err)
+		return
+	}
+
+	state := pars.FromString(b.String())
+	result, err := pars.AsParser(GFF3Parser).Parse(state)
+	if err != nil {
+		t.Errorf("parser returned %v", err)
+		return
+	}
+
+	table := result.Value.(FeatureTable)
+	ff, ok := table.Lookup("NC_001422.1")
+	if !ok {
+		t.Errorf("table.Lookup(%q) failed", "NC_001422.1")
+		return
+	}
+
+	testutils.Equals(t, ff, seq.Features())
+
+	if _, ok := table.Lookup("foo"); ok {
+		t.Errorf("table.Lookup(%q) succeeded, expected failure", "foo")
+	}
+}
+
+func TestGFF3Writer(t *testing.T) {
+	props := gts.Props{}
+	props.Add("locus_tag", "ABC_0001")
+	props.Add("note", "a;b=c")
+	props.Add("pseudo", "")
+
+	p := []byte(strings.Repeat("atgc", 25))
+	ff := []gts.Feature{
+		gts.NewFeature("gene", gts.PartialRange(9, 40, gts.Partial5).Complement(), props),
+		gts.NewFeature("CDS", gts.Join(gts.Range(9, 20), gts.Range(29, 40)).Complement(), props),
+		gts.NewFeature("misc_feature", gts.Between(50), gts.Props{}),
+	}
+	seq := gts.New("seq desc", ff, p)
+
+	exp := "" +
+		"##gff-version 3\n" +
+		"##sequence-region seq 1 100\n" +
+		"seq\t.\tgene\t10\t40\t.\t-\t.\tID=gene-1;start_range=.,10;locus_tag=ABC_0001;Note=a%3Bb%3Dc;pseudo=true\n" +
+		"seq\t.\tCDS\t10\t20\t.\t-\t1\tID=CDS-1;Parent=gene-1;locus_tag=ABC_0001;Note=a%3Bb%3Dc;pseudo=true\n" +
+		"seq\t.\tCDS\t30\t40\t.\t-\t0\tID=CDS-1;Parent=gene-1;locus_tag=ABC_0001;Note=a%3Bb%3Dc;pseudo=true\n" +
+		"seq\t.\tmisc_feature\t50\t50\t.\t+\t.\tID=misc_feature-1\n"
+
+	b := strings.Builder{}
+	n, err := NewWriter(&b, GFF3File).WriteSeq(seq)
+	if n != len(exp) || err != nil {
+		t.Errorf("w.WriteSeq(seq) = (%d, %v), want (%d, nil)", n, err, len(exp))
+	}
+	testutils.DiffLine(t, exp, b.String())
+
+	state := pars.FromString(b.String())
+	result, err := pars.AsParser(GFF3Parser).Parse(state)
+	if err != nil {
+		t.Errorf("parser returned %v", err)
+		return
+	}
+
+	table := result.Value.(FeatureTable)
+	gg, ok := table.Lookup("seq")
+	if !ok || len(gg) != 3 {
+		t.Errorf("table.Lookup(%q) = (%v, %t)", "seq", gg, ok)
+		return
+	}
+
+	testutils.Equals(t, gg[0], ff[0])
+	testutils.Equals(t, gg[1], ff[1])
+	testutils.Equals(t, gg[2].Loc, gts.Location(gts.Point(49)))
+}
+
+func TestGFF3WriterRemote(t *testing.T) {
+	p := []byte(strings.Repeat("atgc", 25))
+	remote := gts.Remote{Accession: "J00194.1", Location: gts.Range(99, 202)}
+	ff := []gts.Feature{
+		gts.NewFeature("misc_feature", remote, gts.Props{}),
+		gts.NewFeature("misc_feature", gts.Join(gts.Range(9, 20), remote), gts.Props{}),
+	}
+	seq := gts.New("seq desc", ff, p)
+
+	exp := "" +
+		"##gff-version 3\n" +
+		"##sequence-region seq 1 100\n" +
+		"# skipped misc_feature J00194.1:100..202 in seq: remote location in J00194.1\n" +
+		"# omitted remote parts of misc_feature join(10..20,J00194.1:100..202) in seq: J00194.1\n" +
+		"seq\t.\tmisc_feature\t10\t20\t.\t+\t.\tID=misc_feature-2\n"
+
+	b := strings.Builder{}
+	n, err := NewWriter(&b, GFF3File).WriteSeq(seq)
+	if n != len(exp) || err != nil {
+		t.Errorf("w.WriteSeq(seq) = (%d, %v), want (%d, nil)", n, err, len(exp))
+	}
+	testutils.DiffLine(t, exp, b.String())
+
+	state := pars.FromString(b.String())
+	result, err := pars.AsParser(GFF3Parser).Parse(state)
+	if err != nil {
+		t.Errorf("parser returned %v", err)
+		return
+	}
+
+	table := result.Value.(FeatureTable)
+	gg, ok := table.Lookup("seq")
+	if !ok || len(gg) != 1 {
+		t.Errorf("table.Lookup(%q) = (%v, %t)", "seq", gg, ok)
+		return
+	}
+	testutils.Equals(t, gg[0].Loc, gts.Location(gts.Range(9, 20)))
+}
+
+func TestGFF3Parser(t *testing.T) {
+	in := "" +
+		"##gff-version 3\n" +
+		"# comment\n" +
+		"ctg123\t.\tgene\t1000\t9000\t.\t+\t.\tID=gene00001;Name=EDEN\n" +
+		"ctg123\t.\tmRNA\t1050\t9000\t.\t+\t.\tID=mRNA00001;Parent=gene00001\n" +
+		"ctg123\t.\tCDS\t1201\t1500\t.\t+\t0\tID=cds00001;Parent=mRNA00001\n" +
+		"ctg123\t.\tCDS\t3000\t3902\t.\t+\t0\tID=cds00001;Parent=mRNA00001\n" +
+		"ctg456\t.\texon\t1\t100\t.\t-\t.\tDbxref=GeneID:1,GeneID:2\n" +
+		"##FASTA\n" +
+		">ctg123\n" +
+		"atgc\n"
+
+	state := pars.FromString(in)
+	result, err := pars.AsParser(GFF3Parser).Parse(state)
+	if err != nil {
+		t.Errorf("parser returned %v", err)
+		return
+	}
+
+	table := result.Value.(FeatureTable)
+	if len(table) != 2 {
+		t.Errorf("len(table) = %d, want 2", len(table))
+		return
+	}
+
+	ff := table[0].Features
+	testutils.Equals(t, table[0].SeqID, "ctg123")
+	testutils.Equals(t, len(ff), 3)
+	testutils.Equals(t, ff[0].Props.Get("Name"), []string{"EDEN"})
+	testutils.Equals(t, ff[2].Loc, gts.Location(gts.Joined{gts.Range(1200, 1500), gts.Range(2999, 3902)}))
+
+	gg := table[1].Features
+	testutils.Equals(t, gg[0].Loc, gts.Range(0, 100).Complement())
+	testutils.Equals(t, gg[0].Props.Get("db_xref"), []string{"GeneID:1", "GeneID:2"})
+
+	fasta, err := FastaParser.Parse(state)
+	if err != nil {
+		t.Errorf("FastaParser returned %v", err)
+		return
+	}
+	testutils.Equals(t, fasta.Value.(Fasta).Desc, "ctg123")
+}
+
+var gff3FailTests = []string{
+	"",
+	"ctg123\t.\tgene\t1000\t9000\t.\t+\t.\tID=gene00001\n",
+	"##gff-version 3\nctg123\t.\tgene\t1000\t9000\t.\t+\n",
+	"##gff-version 3\nctg123\t.\tgene\tfoo\t9000\t.\t+\t.\t.\n",
+	"##gff-version 3\nctg123\t.\tgene\t1000\tbar\t.\t+\t.\t.\n",
+	"##gff-version 3\nctg123\t.\tgene\t9000\t1000\t.\t+\t.\t.\n",
+	"##gff-version 3\nctg123\t.\tgene\t1000\t9000\t.\t+\t.\tfoo\n",
+}
+
+func TestGFF3ParserFail(t *testing.T) {
+	parser := pars.AsParser(GFF3Parser)
+	for _, in := range gff3FailTests {
+		state := pars.FromString(in)
+		if err := parser(state, pars.Void); err == nil {
+			t.Errorf("while parsing`\n%s\n`: expected error", in)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package seqio
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-gts/gts"
+)
+
+const indexHeader = "#gts-index"
+
+// IndexEntry represents the location of a single record in a sequence file.
+// Offset and Size are the byte offset and byte size of the record in the file
+// and Length is the length of the sequence.
+type IndexEntry struct {
+	ID     string
+	Length int
+	Offset int64
+	Size   int64
+}
+
+// Index represents an index of the records in a sequence file. Size is the
+// size of the indexed file in bytes.
+type Index struct {
+	Size    int64
+	Entries []IndexEntry
+	ids     map[string]int
+}
+
+// NewIndex creates a new index for a file of the given size.
+func NewIndex(size int64, entries []IndexEntry) *Index {
+	idx := &Index{size, nil, make(map[string]int)}
+	for _, entry := range entries {
+		idx.add(entry)
+	}
+	return idx
+}
+
+func (idx *Index) add(entry IndexEntry) {
+	if _, ok := idx.ids[entry.ID]; !ok {
+		idx.ids[entry.ID] = len(idx.Entries)
+	}
+	idx.Entries = append(idx.Entries, entry)
+}
+
+// Len returns the number of records in the index.
+func (idx Index) Len() int {
+	return len(idx.Entries)
+}
+
+// Lookup returns the first entry with the given ID.
+func (idx Index) Lookup(id string) (IndexEntry, bool) {
+	if i, ok := idx.ids[id]; ok {
+		return idx.Entries[i], true
+	}
+	return IndexEntry{}, false
+}
+
+// WriteTo satisfies the io.WriterTo interface.
+func (idx Index) WriteTo(w io.Writer) (int64, error) {
+	b := strings.Builder{}
+	b.WriteString(fmt.Sprintf("%s\t%d\n", indexHeader, idx.Size))
+	for _, e := range idx.Entries {
+		b.WriteString(fmt.Sprintf("%s\t%d\t%d\t%d\n", e.ID, e.Length, e.Offset, e.Size))
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ReadIndex reads an index written by Index.WriteTo.
+func ReadIndex(r io.Reader) (*Index, error) {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("empty index")
+	}
+
+	fields := strings.Split(scanner.Text(), "\t")
+	if len(fields) != 2 || fields[0] != indexHeader {
+		return nil, fmt.Errorf("expected index header %q at line 1", indexHeader)
+	}
+	size, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file size at line 1: %v", err)
+	}
+
+	idx := NewIndex(size, nil)
+	for line := 2; scanner.Scan(); line++ {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("expected 4 columns at line %d, got %d", line, len(fields))
+		}
+		length, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid length at line %d: %v", line, err)
+		}
+		offset, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset at line %d: %v", line, err)
+		}
+		n, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size at line %d: %v", line, err)
+		}
+		idx.add(IndexEntry{fields[0], length, offset, n})
+	}
+
+	return idx, scanner.Err()
+}
+
+// BuildIndex reads all of the records in a sequence file to build an index.
+// GenBank, EMBL, FASTQ, and FASTA files are supported.
+func BuildIndex(r io.Reader) (*Index, error) {
+	idx := NewIndex(0, nil)
+	size, err := splitRecords(r, func(offset int64, p []byte) error {
+		scanner := NewAutoScanner(bytes.NewReader(p))
+		if !scanner.Scan() {
+			err := scanner.Err()
+			if err == nil {
+				err = errors.New("no sequence found")
+			}
+			return fmt.Errorf("in record %d at byte %d: %v", idx.Len()+1, offset, err)
+		}
+		seq := scanner.Value()
+		idx.add(IndexEntry{SequenceID(seq), gts.Len(seq), offset, int64(len(p))})
+		return nil
+	})
+	idx.Size = size
+	return idx, err
+}
+
+// ReadRecord reads the record referenced by the index entry.
+func ReadRecord(r io.ReaderAt, entry IndexEntry) (gts.Sequence, error) {
+	scanner := NewAutoScanner(io.NewSectionReader(r, entry.Offset, entry.Size))
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("no sequence found at byte %d", entry.Offset)
+	}
+	return scanner.Value(), nil
+}
+
+// recordSplitter decides where the records in a sequence file begin and end.
+// It is given each line of the file in order and returns whether the line
+// starts a new record and whether it ends the current record.
+type recordSplitter func(line []byte) (start, end bool)
+
+func terminatedSplitter(line []byte) (bool, bool) {
+	return false, bytes.HasPrefix(line, []byte("//"))
+}
+
+func fastaSplitter(line []byte) (bool, bool) {
+	return len(line) > 0 && line[0] == '>', false
+}
+
+func makeFastqSplitter() recordSplitter {
+	seq, qual, sep := 0, 0, false
+	return func(line []byte) (bool, bool) {
+		n := len(bytes.TrimRight(line, "\r\n"))
+		switch {
+		case sep:
+			qual += n
+			if qual >= seq {
+				seq, qual, sep = 0, 0, false
+				return false, true
+			}
+		case seq == 0 && len(line) > 0 && line[0] == '@':
+			return true, false
+		case len(line) > 0 && line[0] == '+':
+			sep = true
+			if seq == 0 {
+				sep = false
+				return false, true
+			}
+		default:
+			seq += n
+		}
+		return false, false
+	}
+}
+
+func detectSplitter(line []byte) (recordSplitter, error) {
+	switch {
+	case bytes.HasPrefix(line, []byte("LOCUS")), bytes.HasPrefix(line, []byte("ID ")):
+		return terminatedSplitter, nil
+	case line[0] == '>':
+		return fastaSplitter, nil
+	case line[0] == '@':
+		return makeFastqSplitter(), nil
+	default:
+		return nil, errors.New("unsupported file format for indexing")
+	}
+}
+
+// splitRecords calls the given function with the offset and content of each
+// record in a sequence file, and returns the total number of bytes read.
+func splitRecords(r io.Reader, f func(offset int64, p []byte) error) (int64, error) {
+	br := bufio.NewReader(r)
+	if isCompressed(br) {
+		return 0, ErrCompressedIndex
+	}
+
+	var split recordSplitter
+	offset, start := int64(0), int64(0)
+	record := []byte{}
+
+	emit := func() error {
+		if len(record) == 0 {
+			return nil
+		}
+		err := f(start, record)
+		record = record[:0]
+		return err
+	}
+
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			blank := len(bytes.TrimSpace(line)) == 0
+			switch {
+			case len(record) == 0 && blank:
+			case split == nil:
+				s, err := detectSplitter(line)
+				if err != nil {
+					return offset, err
+				}
+				split = s
+				fallthrough
+			default:
+				head, tail := split(line)
+				if head {
+					if err := emit(); err != nil {
+						return offset, err
+					}
+				}
+				if len(record) == 0 {
+					start = offset
+				}
+				record = append(record, line...)
+				if tail {
+					if err := emit(); err != nil {
+						return offset, err
+					}
+				}
+			}
+			offset += int64(len(line))
+		}
+		if err == io.EOF {
+			return offset, emit()
+		}
+		if err != nil {
+			return offset, err
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package seqio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/internal/testutils"
+)
+
+var indexTests = [][]string{
+	{"NC_001422.gb", "NC_001422_part.gb", "NC_000913.3.min.gb"},
+	{"NC_001422.embl", "ena_sample.embl"},
+	{"NC_001422.fasta", "NC_001422_part.fasta"},
+	{"sample.fastq", "SRR001666_1.fastq"},
+}
+
+func TestIndex(t *testing.T) {
+	for _, filenames := range indexTests {
+		b := strings.Builder{}
+		for _, filename := range filenames {
+			b.WriteString(testutils.ReadTestfile(t, filename))
+			b.WriteString("\n")
+		}
+		in := b.String()
+
+		exp := []gts.Sequence{}
+		scanner := NewAutoScanner(strings.NewReader(in))
+		for scanner.Scan() {
+			exp = append(exp, scanner.Value())
+		}
+
+		idx, err := BuildIndex(strings.NewReader(in))
+		if err != nil {
+			t.Errorf("%v: BuildIndex: %v", filenames, err)
+			continue
+		}
+		if idx.Size != int64(len(in)) {
+			t.Errorf("%v: idx.Size = %d, want %d", filenames, idx.Size, len(in))
+		}
+		if idx.Len() < len(exp) || idx.Len() == 0 {
+			t.Errorf("%v: idx.Len() = %d, want at least %d", filenames, idx.Len(), len(exp))
+			continue
+		}
+
+		r := strings.NewReader(in)
+		for i, entry := range idx.Entries {
+			seq, err := ReadRecord(r, entry)
+			if err != nil {
+				t.Errorf("%v: ReadRecord(r, %v): %v", filenames, entry, err)
+				continue
+			}
+			if i < len(exp) {
+				testutils.Equals(t, seq, exp[i])
+			}
+			if entry.ID != SequenceID(seq) || entry.Length != gts.Len(seq) {
+				t.Errorf("%v: entry %d = %v", filenames, i, entry)
+			}
+			if e, ok := idx.Lookup(entry.ID); !ok || e.Offset > entry.Offset {
+				t.Errorf("idx.Lookup(%q) = %v, %t", entry.ID, e, ok)
+			}
+		}
+
+		buf := bytes.Buffer{}
+		if _, err := idx.WriteTo(&buf); err != nil {
+			t.Errorf("idx.WriteTo: %v", err)
+		}
+		out, err := ReadIndex(&buf)
+		if err != nil {
+			t.Errorf("ReadIndex: %v", err)
+			continue
+		}
+		testutils.Equals(t, out, idx)
+	}
+}
+
+func TestIndexLookup(t *testing.T) {
+	idx := NewIndex(100, []IndexEntry{
+		{"foo", 10, 0, 40},
+		{"bar", 10, 40, 30},
+		{"foo", 10, 70, 30},
+	})
+	if e, ok := idx.Lookup("foo"); !ok || e.Offset != 0 {
+		t.Errorf("idx.Lookup(%q) = %v, %t, want offset 0", "foo", e, ok)
+	}
+	if e, ok := idx.Lookup("bar"); !ok || e.Offset != 40 {
+		t.Errorf("idx.Lookup(%q) = %v, %t, want offset 40", "bar", e, ok)
+	}
+	if _, ok := idx.Lookup("baz"); ok {
+		t.Errorf("idx.Lookup(%q) succeeded, want failure", "baz")
+	}
+}
+
+func TestBuildIndexFail(t *testing.T) {
+	in := testutils.ReadTestfile(t, "NC_001422.gb")
+	tests := []string{
+		"foo\n",
+		in[:len(in)-100] + "//\n",
+		"[]\n",
+	}
+	for _, in := range tests {
+		if _, err := BuildIndex(strings.NewReader(in)); err == nil {
+			t.Errorf("expected error in BuildIndex for:\n%.40s", in)
+		}
+	}
+}
+
+func TestReadIndexFail(t *testing.T) {
+	tests := []string{
+		"",
+		"foo\t0\n",
+		"#gts-index\tfoo\n",
+		"#gts-index\t0\nfoo\t1\t2\n",
+		"#gts-index\t0\nfoo\tbar\t2\t3\n",
+		"#gts-index\t0\nfoo\t1\tbar\t3\n",
+		"#gts-index\t0\nfoo\t1\t2\tbar\n",
+	}
+	for _, in := range tests {
+		if _, err := ReadIndex(strings.NewReader(in)); err == nil {
+			t.Errorf("expected error in ReadIndex(%q)", in)
+		}
+	}
+}
@@ -432,7 +432,7 @@ func INSDCTableParser(prefix string) pars.Parser {
 		pre, key, pst, loc := tmp.pre, tmp.key, tmp.pst, tmp.loc
 		depth := pre + len(key) + pst
 
-		keylineParser := featureKeylineParser(prefix+strings.Repeat(" ", pre), depth)
+		keylineParser := featureKeylineParser(prefix+strings.Repeat(" ", pre), len(prefix)+depth)
 
 		qualifierParser := QualifierParser(prefix + strings.Repeat(" ", depth))
 		qualifiersParser := pars.Many(qualifierParser)
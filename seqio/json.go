@@ -0,0 +1,578 @@
+package seqio
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-gts/gts"
+	"github.com/go-pars/pars"
+)
+
+const jsonDateLayout = "2006-01-02"
+
+type jsonLocation struct {
+	Type      string         `json:"type"`
+	Start     *int           `json:"start,omitempty"`
+	End       *int           `json:"end,omitempty"`
+	Partial5  bool           `json:"partial5,omitempty"`
+	Partial3  bool           `json:"partial3,omitempty"`
+	Strand    string         `json:"strand"`
+	Accession string         `json:"accession,omitempty"`
+	Location  *jsonLocation  `json:"location,omitempty"`
+	Locations []jsonLocation `json:"locations,omitempty"`
+}
+
+func newJSONRange(kind string, start, end int, strand string) jsonLocation {
+	return jsonLocation{Type: kind, Start: &start, End: &end, Strand: strand}
+}
+
+func encodeJSONLocation(loc gts.Location, strand string) (jsonLocation, error) {
+	switch v := loc.(type) {
+	case gts.Between:
+		return newJSONRange("between", int(v), int(v), strand), nil
+	case gts.Point:
+		return newJSONRange("point", int(v), int(v)+1, strand), nil
+	case gts.Ranged:
+		jl := newJSONRange("range", v.Start, v.End, strand)
+		jl.Partial5, jl.Partial3 = v.Partial.Partial5, v.Partial.Partial3
+		return jl, nil
+	case gts.Ambiguous:
+		return newJSONRange("ambiguous", v.Start, v.End, strand), nil
+	case gts.Joined:
+		return encodeJSONLocations("join", v, strand)
+	case gts.Ordered:
+		return encodeJSONLocations("order", v, strand)
+	case gts.Remote:
+		child, err := encodeJSONLocation(v.Location, "+")
+		if err != nil {
+			return jsonLocation{}, err
+		}
+		return jsonLocation{Type: "remote", Strand: strand, Accession: v.Accession, Location: &child}, nil
+	case gts.Complemented:
+		if strand == "+" {
+			return encodeJSONLocation(v.Location, "-")
+		}
+		return encodeJSONLocation(v.Location, "+")
+	default:
+		return jsonLocation{}, fmt.Errorf("gts does not know how to encode a location of type `%T` as JSON", loc)
+	}
+}
+
+func encodeJSONLocations(kind string, locs []gts.Location, strand string) (jsonLocation, error) {
+	jl := jsonLocation{Type: kind, Strand: strand}
+	for _, loc := range locs {
+		child, err := encodeJSONLocation(loc, "+")
+		if err != nil {
+			return jsonLocation{}, err
+		}
+		jl.Locations = append(jl.Locations, child)
+	}
+	return jl, nil
+}
+
+func decodeJSONLocation(jl jsonLocation) (gts.Location, error) {
+	var loc gts.Location
+
+	switch jl.Type {
+	case "join", "order":
+		locs := make([]gts.Location, len(jl.Locations))
+		for i, child := range jl.Locations {
+			l, err := decodeJSONLocation(child)
+			if err != nil {
+				return nil, err
+			}
+			locs[i] = l
+		}
+		if jl.Type == "join" {
+			loc = gts.Joined(locs)
+		} else {
+			loc = gts.Ordered(locs)
+		}
+
+	case "remote":
+		if jl.Accession == "" || jl.Location == nil {
+			return nil, fmt.Errorf("remote location requires an accession and a location")
+		}
+		l, err := decodeJSONLocation(*jl.Location)
+		if err != nil {
+			return nil, err
+		}
+		loc = gts.Remote{Accession: jl.Accession, Location: l}
+
+	default:
+		if jl.Start == nil || jl.End == nil {
+			return nil, fmt.Errorf("%s location requires a start and an end", jl.Type)
+		}
+		start, end := *jl.Start, *jl.End
+		switch jl.Type {
+		case "between":
+			loc = gts.Between(start)
+		case "point":
+			loc = gts.Point(start)
+		case "range":
+			loc = gts.PartialRange(start, end, gts.Partial{Partial5: jl.Partial5, Partial3: jl.Partial3})
+		case "ambiguous":
+			loc = gts.Ambiguous{Start: start, End: end}
+		default:
+			return nil, fmt.Errorf("unknown location type: %q", jl.Type)
+		}
+	}
+
+	switch jl.Strand {
+	case "", "+":
+		return loc, nil
+	case "-":
+		return gts.Complemented{Location: loc}, nil
+	default:
+		return nil, fmt.Errorf("unknown strand: %q", jl.Strand)
+	}
+}
+
+// jsonProps encodes the qualifiers as a JSON object while retaining the order
+// of the qualifier names.
+type jsonProps gts.Props
+
+func (props jsonProps) MarshalJSON() ([]byte, error) {
+	b := bytes.Buffer{}
+	b.WriteByte('{')
+	for i, prop := range props {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, err := json.Marshal(prop[0])
+		if err != nil {
+			return nil, err
+		}
+		values, err := json.Marshal(append([]string{}, prop[1:]...))
+		if err != nil {
+			return nil, err
+		}
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(values)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func (props *jsonProps) UnmarshalJSON(p []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("expected JSON object for qualifiers")
+	}
+
+	ret := gts.Props{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name := tok.(string)
+		values := []string{}
+		if err := dec.Decode(&values); err != nil {
+			return err
+		}
+		ret = append(ret, append([]string{name}, values...))
+	}
+
+	*props = jsonProps(ret)
+	return nil
+}
+
+type jsonFeature struct {
+	Key        string       `json:"key"`
+	Location   jsonLocation `json:"location"`
+	Qualifiers jsonProps    `json:"qualifiers"`
+}
+
+type jsonPair struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type jsonOrganism struct {
+	Species string   `json:"species"`
+	Name    string   `json:"name"`
+	Taxon   []string `json:"taxon"`
+}
+
+type jsonReference struct {
+	Number  int               `json:"number"`
+	Info    string            `json:"info,omitempty"`
+	Authors string            `json:"authors,omitempty"`
+	Group   string            `json:"group,omitempty"`
+	Title   string            `json:"title,omitempty"`
+	Journal string            `json:"journal,omitempty"`
+	Xref    map[string]string `json:"xref,omitempty"`
+	Comment string            `json:"comment,omitempty"`
+}
+
+type jsonContig struct {
+	Accession string `json:"accession"`
+	Start     int    `json:"start"`
+	End       int    `json:"end"`
+}
+
+type jsonGenBankFields struct {
+	LocusName  string          `json:"locus_name"`
+	Molecule   string          `json:"molecule"`
+	Topology   string          `json:"topology"`
+	Division   string          `json:"division"`
+	Date       string          `json:"date"`
+	Definition string          `json:"definition"`
+	Accession  string          `json:"accession"`
+	Version    string          `json:"version"`
+	DBLink     []jsonPair      `json:"dblink,omitempty"`
+	Keywords   []string        `json:"keywords"`
+	Source     jsonOrganism    `json:"source"`
+	References []jsonReference `json:"references,omitempty"`
+	Comments   []string        `json:"comments,omitempty"`
+	Extra      []jsonPair      `json:"extra,omitempty"`
+	Contig     *jsonContig     `json:"contig,omitempty"`
+	Region     []int           `json:"region,omitempty"`
+}
+
+func encodeGenBankFields(gbf GenBankFields) jsonGenBankFields {
+	jf := jsonGenBankFields{
+		LocusName:  gbf.LocusName,
+		Molecule:   string(gbf.Molecule),
+		Topology:   gbf.Topology.String(),
+		Division:   gbf.Division,
+		Date:       gbf.Date.ToTime().Format(jsonDateLayout),
+		Definition: gbf.Definition,
+		Accession:  gbf.Accession,
+		Version:    gbf.Version,
+		Keywords:   gbf.Keywords,
+		Source:     jsonOrganism(gbf.Source),
+		Comments:   gbf.Comments,
+	}
+
+	for _, pair := range gbf.DBLink {
+		jf.DBLink = append(jf.DBLink, jsonPair(pair))
+	}
+	for _, ref := range gbf.References {
+		jf.References = append(jf.References, jsonReference(ref))
+	}
+	for _, field := range gbf.Extra {
+		jf.Extra = append(jf.Extra, jsonPair{field.Name, field.Value})
+	}
+	if gbf.Contig.Accession != "" {
+		head, tail := gts.Unpack(gbf.Contig.Region)
+		jf.Contig = &jsonContig{gbf.Contig.Accession, head, tail}
+	}
+	if region, ok := gbf.Region.(gts.Segment); ok {
+		jf.Region = []int{region[0], region[1]}
+	}
+
+	return jf
+}
+
+func decodeGenBankFields(jf jsonGenBankFields) (GenBankFields, error) {
+	gbf := GenBankFields{
+		LocusName:  jf.LocusName,
+		Division:   jf.Division,
+		Definition: jf.Definition,
+		Accession:  jf.Accession,
+		Version:    jf.Version,
+		Keywords:   jf.Keywords,
+		Source:     Organism(jf.Source),
+		Comments:   jf.Comments,
+	}
+
+	mol, err := gts.AsMolecule(jf.Molecule)
+	if err != nil {
+		return gbf, err
+	}
+	gbf.Molecule = mol
+
+	top, err := gts.AsTopology(jf.Topology)
+	if err != nil {
+		return gbf, err
+	}
+	gbf.Topology = top
+
+	t, err := time.Parse(jsonDateLayout, jf.Date)
+	if err != nil {
+		return gbf, fmt.Errorf("malformed date %q: %v", jf.Date, err)
+	}
+	gbf.Date = FromTime(t)
+
+	for _, pair := range jf.DBLink {
+		gbf.DBLink = append(gbf.DBLink, Pair(pair))
+	}
+	for _, ref := range jf.References {
+		gbf.References = append(gbf.References, Reference(ref))
+	}
+	for _, pair := range jf.Extra {
+		gbf.Extra = append(gbf.Extra, GenBankExtraField(pair.Key, pair.Value))
+	}
+	if jf.Contig != nil {
+		gbf.Contig = Contig{jf.Contig.Accession, gts.Segment{jf.Contig.Start, jf.Contig.End}}
+	}
+	if len(jf.Region) == 2 {
+		gbf.Region = gts.Segment{jf.Region[0], jf.Region[1]}
+	}
+
+	return gbf, nil
+}
+
+type jsonRecord struct {
+	ID          string             `json:"id"`
+	Description *string            `json:"description,omitempty"`
+	Quality     *string            `json:"quality,omitempty"`
+	Fields      *jsonGenBankFields `json:"fields,omitempty"`
+	Features    []jsonFeature      `json:"features"`
+	Sequence    string             `json:"sequence"`
+}
+
+func encodeJSONRecord(seq gts.Sequence) (jsonRecord, error) {
+	rec := jsonRecord{
+		ID:       SequenceID(seq),
+		Features: []jsonFeature{},
+		Sequence: string(seq.Bytes()),
+	}
+
+	switch info := seq.Info().(type) {
+	case GenBankFields:
+		jf := encodeGenBankFields(info)
+		rec.Fields = &jf
+	case FastqFields:
+		desc, qual := info.Desc, string(fitQuality(info.Quality, gts.Len(seq)))
+		rec.Description, rec.Quality = &desc, &qual
+	case string:
+		rec.Description = &info
+	case fmt.Stringer:
+		desc := info.String()
+		rec.Description = &desc
+	default:
+		return rec, fmt.Errorf("gts does not know how to format a sequence with metadata type `%T` as JSON", info)
+	}
+
+	for _, f := range seq.Features() {
+		loc, err := encodeJSONLocation(f.Loc, "+")
+		if err != nil {
+			return rec, err
+		}
+		rec.Features = append(rec.Features, jsonFeature{f.Key, loc, jsonProps(f.Props)})
+	}
+
+	return rec, nil
+}
+
+func decodeJSONRecord(rec jsonRecord) (gts.Sequence, error) {
+	ff := make(gts.FeatureSlice, len(rec.Features))
+	for i, jf := range rec.Features {
+		loc, err := decodeJSONLocation(jf.Location)
+		if err != nil {
+			return nil, err
+		}
+		props := gts.Props(jf.Qualifiers)
+		if props == nil {
+			props = gts.Props{}
+		}
+		ff[i] = gts.NewFeature(jf.Key, loc, props)
+	}
+
+	data := []byte(rec.Sequence)
+
+	switch {
+	case rec.Fields != nil:
+		gbf, err := decodeGenBankFields(*rec.Fields)
+		if err != nil {
+			return nil, err
+		}
+		return GenBank{gbf, ff, NewOrigin(data)}, nil
+
+	case rec.Quality != nil:
+		desc := ""
+		if rec.Description != nil {
+			desc = *rec.Description
+		}
+		fq := Fastq{FastqFields{desc, []byte(*rec.Quality)}, data}
+		return fq.WithFeatures(ff), nil
+
+	default:
+		desc := rec.ID
+		if rec.Description != nil {
+			desc = *rec.Description
+		}
+		if len(ff) == 0 {
+			return Fasta{desc, data}, nil
+		}
+		return gts.New(desc, ff, data), nil
+	}
+}
+
+// JSONWriter writes a gts.Sequence to an io.Writer as a JSON object. If lines
+// is true, each sequence is written as a single line of JSON so that the
+// output will be in JSON Lines format. Otherwise, the objects are indented and
+// written as the elements of a single JSON array, which is terminated when the
+// writer is closed.
+type JSONWriter struct {
+	w     io.Writer
+	lines bool
+	count int
+}
+
+// NewJSONWriter creates a new JSONWriter.
+func NewJSONWriter(w io.Writer, lines bool) *JSONWriter {
+	return &JSONWriter{w, lines, 0}
+}
+
+// WriteSeq satisfies the seqio.SeqWriter interface.
+func (w *JSONWriter) WriteSeq(seq gts.Sequence) (int, error) {
+	rec, err := encodeJSONRecord(seq)
+	if err != nil {
+		return 0, err
+	}
+
+	if w.lines {
+		p, err := json.Marshal(rec)
+		if err != nil {
+			return 0, err
+		}
+		w.count++
+		return w.w.Write(append(p, '\n'))
+	}
+
+	p, err := json.MarshalIndent(rec, "  ", "  ")
+	if err != nil {
+		return 0, err
+	}
+
+	sep := ",\n  "
+	if w.count == 0 {
+		sep = "[\n  "
+	}
+	w.count++
+	return w.w.Write(append([]byte(sep), p...))
+}
+
+// Close terminates the JSON array. An empty array is written if no sequence
+// has been written. Nothing is written in JSON Lines format. Closing the writer
+// will not close the underlying writer.
+func (w *JSONWriter) Close() error {
+	if w.lines {
+		return nil
+	}
+	s := "\n]\n"
+	if w.count == 0 {
+		s = "[]\n"
+	}
+	_, err := io.WriteString(w.w, s)
+	return err
+}
+
+func skipJSONSpace(state *pars.State) error {
+	for {
+		if err := state.Request(1); err != nil {
+			return err
+		}
+		switch state.Buffer()[0] {
+		case ' ', '\t', '\r', '\n':
+			state.Advance()
+		default:
+			return nil
+		}
+	}
+}
+
+// scanJSONObject reads the bytes of a single JSON object from the state.
+func scanJSONObject(state *pars.State) ([]byte, error) {
+	p := []byte{}
+	depth, quoted, escaped := 0, false, false
+
+	for {
+		if err := state.Request(1); err != nil {
+			return nil, pars.NewError("unexpected end of JSON object", state.Position())
+		}
+		c := state.Buffer()[0]
+		state.Advance()
+		p = append(p, c)
+
+		switch {
+		case escaped:
+			escaped = false
+		case quoted:
+			switch c {
+			case '\\':
+				escaped = true
+			case '"':
+				quoted = false
+			}
+		default:
+			switch c {
+			case '"':
+				quoted = true
+			case '{':
+				depth++
+			case '}':
+				depth--
+				if depth == 0 {
+					return p, nil
+				}
+			}
+		}
+	}
+}
+
+// endJSONArray consumes the closing bracket of a JSON array, which must be
+// followed by the end of input.
+func endJSONArray(state *pars.State) error {
+	state.Advance()
+	if err := skipJSONSpace(state); err != nil {
+		return err
+	}
+	return pars.NewError("unexpected data after JSON array", state.Position())
+}
+
+// JSONParser attempts to parse a single sequence encoded as a JSON object.
+// The objects may either be concatenated as in JSON Lines format, or be the
+// elements of a single JSON array. An empty array is read as the end of input.
+func JSONParser(state *pars.State, result *pars.Result) error {
+	if err := skipJSONSpace(state); err != nil {
+		return err
+	}
+
+	switch state.Buffer()[0] {
+	case '[', ',':
+		start := state.Buffer()[0] == '['
+		state.Advance()
+		if err := skipJSONSpace(state); err != nil {
+			return pars.NewError("unexpected end of JSON array", state.Position())
+		}
+		// An empty array contains no sequences.
+		if start && state.Buffer()[0] == ']' {
+			return endJSONArray(state)
+		}
+	case ']':
+		return endJSONArray(state)
+	}
+
+	pos := state.Position()
+	if state.Buffer()[0] != '{' {
+		return pars.NewError("expected `{`", pos)
+	}
+
+	p, err := scanJSONObject(state)
+	if err != nil {
+		return err
+	}
+
+	rec := jsonRecord{}
+	if err := json.Unmarshal(p, &rec); err != nil {
+		return pars.NewError(fmt.Sprintf("malformed JSON sequence: %v", err), pos)
+	}
+
+	seq, err := decodeJSONRecord(rec)
+	if err != nil {
+		return pars.NewError(err.Error(), pos)
+	}
+
+	state.Clear()
+	result.SetValue(seq)
+	return nil
+}
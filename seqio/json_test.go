@@ -0,0 +1,291 @@
+package seqio
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/internal/testutils"
+	"github.com/go-pars/pars"
+)
+
+func TestJSONGenBankIO(t *testing.T) {
+	files := []string{
+		"NC_001422.gb",
+		"NC_000913.3.min.gb",
+		"NC_001422_part.gb",
+	}
+	for _, file := range files {
+		in := testutils.ReadTestfile(t, file)
+		scanner := NewScanner(GenBankParser, strings.NewReader(in))
+		if !scanner.Scan() {
+			t.Errorf("in file %q, scanner.Err() = %v", file, scanner.Err())
+			continue
+		}
+		seq := scanner.Value()
+
+		for _, lines := range []bool{false, true} {
+			b := strings.Builder{}
+			w := NewJSONWriter(&b, lines)
+			if _, err := w.WriteSeq(seq); err != nil {
+				t.Errorf("in file %q, w.WriteSeq(seq): %v", file, err)
+				continue
+			}
+			if err := w.Close(); err != nil {
+				t.Errorf("in file %q, w.Close(): %v", file, err)
+				continue
+			}
+
+			scanner := NewAutoScanner(strings.NewReader(b.String()))
+			if !scanner.Scan() {
+				t.Errorf("in file %q, scanner.Err() = %v", file, scanner.Err())
+				continue
+			}
+			out := scanner.Value()
+
+			if _, ok := out.(GenBank); !ok {
+				t.Errorf("scanner.Value().(type) = %T, want %T", out, GenBank{})
+			}
+
+			c := strings.Builder{}
+			if _, err := (GenBankWriter{&c}).WriteSeq(out); err != nil {
+				t.Errorf("in file %q, w.WriteSeq(out): %v", file, err)
+				continue
+			}
+			testutils.DiffLine(t, in, c.String())
+		}
+	}
+}
+
+func TestJSONFastaIO(t *testing.T) {
+	in := testutils.ReadTestfile(t, "NC_001422.fasta")
+	scanner := NewScanner(FastaParser, strings.NewReader(in))
+	b := strings.Builder{}
+	w := NewWriter(&b, JSONLinesFile)
+	for scanner.Scan() {
+		if _, err := w.WriteSeq(scanner.Value()); err != nil {
+			t.Errorf("w.WriteSeq(seq): %v", err)
+		}
+	}
+
+	scanner = NewAutoScanner(strings.NewReader(b.String()))
+	c := strings.Builder{}
+	w = FastaWriter{&c}
+	for scanner.Scan() {
+		seq := scanner.Value()
+		if _, ok := seq.(Fasta); !ok {
+			t.Errorf("scanner.Value().(type) = %T, want %T", seq, Fasta{})
+		}
+		if _, err := w.WriteSeq(seq); err != nil {
+			t.Errorf("w.WriteSeq(seq): %v", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Errorf("scanner.Err(): %v", err)
+	}
+	testutils.DiffLine(t, in, c.String())
+}
+
+func TestJSONArray(t *testing.T) {
+	in := testutils.ReadTestfile(t, "NC_001422.fasta")
+	scanner := NewScanner(FastaParser, strings.NewReader(in))
+	if !scanner.Scan() {
+		t.Fatalf("scanner.Err() = %v", scanner.Err())
+	}
+	seq := scanner.Value()
+
+	b := strings.Builder{}
+	w := NewJSONWriter(&b, false)
+	for i := 0; i < 2; i++ {
+		if _, err := w.WriteSeq(seq); err != nil {
+			t.Errorf("w.WriteSeq(seq): %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("w.Close(): %v", err)
+	}
+
+	recs := []jsonRecord{}
+	if err := json.Unmarshal([]byte(b.String()), &recs); err != nil {
+		t.Errorf("json.Unmarshal: %v", err)
+	}
+	testutils.Equals(t, len(recs), 2)
+
+	scanner = NewAutoScanner(strings.NewReader(b.String()))
+	n := 0
+	for scanner.Scan() {
+		n++
+	}
+	if err := scanner.Err(); err != nil {
+		t.Errorf("scanner.Err(): %v", err)
+	}
+	testutils.Equals(t, n, 2)
+
+	b.Reset()
+	w = NewJSONWriter(&b, false)
+	if err := w.Close(); err != nil {
+		t.Errorf("w.Close(): %v", err)
+	}
+	testutils.Equals(t, b.String(), "[]\n")
+}
+
+func TestJSONArrayEmpty(t *testing.T) {
+	b := strings.Builder{}
+	w := NewJSONWriter(&b, false)
+	if err := w.Close(); err != nil {
+		t.Errorf("w.Close(): %v", err)
+	}
+
+	for _, in := range []string{b.String(), "[]", " [ \n ] \n"} {
+		for _, scanner := range []*Scanner{
+			NewAutoScanner(strings.NewReader(in)),
+			NewScanner(JSONParser, strings.NewReader(in)),
+		} {
+			if scanner.Scan() {
+				t.Errorf("scanner.Scan() = true for %q, want false", in)
+			}
+			if err := scanner.Err(); err != nil {
+				t.Errorf("scanner.Err() = %v for %q, want nil", err, in)
+			}
+		}
+	}
+}
+
+func TestJSONFastqIO(t *testing.T) {
+	in := testutils.ReadTestfile(t, "sample.fastq")
+	scanner := NewScanner(FastqParser, strings.NewReader(in))
+	b := strings.Builder{}
+	w := NewWriter(&b, JSONLinesFile)
+	for scanner.Scan() {
+		if _, err := w.WriteSeq(scanner.Value()); err != nil {
+			t.Errorf("w.WriteSeq(seq): %v", err)
+		}
+	}
+
+	scanner = NewAutoScanner(strings.NewReader(b.String()))
+	c := strings.Builder{}
+	w = FastqWriter{&c}
+	n := 0
+	for scanner.Scan() {
+		if _, err := w.WriteSeq(scanner.Value()); err != nil {
+			t.Errorf("w.WriteSeq(seq): %v", err)
+		}
+		n++
+	}
+	if err := scanner.Err(); err != nil {
+		t.Errorf("scanner.Err(): %v", err)
+	}
+	if n != 2 {
+		t.Errorf("scanned %d sequences, want 2", n)
+	}
+	testutils.DiffLine(t, in, c.String())
+}
+
+func TestJSONLocation(t *testing.T) {
+	locs := []gts.Location{
+		gts.Between(3),
+		gts.Point(3),
+		gts.Range(3, 10),
+		gts.PartialRange(3, 10, gts.Partial5),
+		gts.Ambiguous{Start: 3, End: 10},
+		gts.Range(3, 10).Complement(),
+		gts.Join(gts.Range(3, 10), gts.Range(20, 30)),
+		gts.Join(gts.Range(3, 10), gts.Range(20, 30)).Complement(),
+		gts.Joined{gts.Range(20, 30).Complement(), gts.Range(3, 10).Complement()},
+		gts.Order(gts.Range(3, 10), gts.Point(20)),
+		gts.Remote{Accession: "J00194.1", Location: gts.Range(99, 202)},
+		gts.Remote{Accession: "J00194.1", Location: gts.Range(99, 202)}.Complement(),
+		gts.Joined{gts.Range(3, 10), gts.Remote{Accession: "J00194.1", Location: gts.Range(99, 202)}},
+	}
+
+	for _, loc := range locs {
+		jl, err := encodeJSONLocation(loc, "+")
+		if err != nil {
+			t.Errorf("encodeJSONLocation(%s): %v", loc, err)
+			continue
+		}
+		out, err := decodeJSONLocation(jl)
+		if err != nil {
+			t.Errorf("decodeJSONLocation(%s): %v", loc, err)
+			continue
+		}
+		testutils.Equals(t, out, loc)
+	}
+}
+
+func TestJSONParser(t *testing.T) {
+	in := `[
+  {
+    "id": "foo",
+    "features": [
+      {
+        "key": "CDS",
+        "location": {"type": "range", "start": 0, "end": 6, "strand": "-"},
+        "qualifiers": {"gene": ["bar"], "pseudo": [], "note": ["a", "b"]}
+      }
+    ],
+    "sequence": "atgtaa"
+  },
+  {"id": "baz", "description": "baz qux", "features": [], "sequence": "atg"}
+]
+`
+	scanner := NewAutoScanner(strings.NewReader(in))
+
+	if !scanner.Scan() {
+		t.Fatalf("scanner.Err() = %v", scanner.Err())
+	}
+	seq := scanner.Value()
+	testutils.Equals(t, seq.Info(), "foo")
+	testutils.Equals(t, seq.Features(), gts.FeatureSlice{
+		gts.NewFeature("CDS", gts.Range(0, 6).Complement(), gts.Props{
+			{"gene", "bar"}, {"pseudo"}, {"note", "a", "b"},
+		}),
+	})
+	testutils.Equals(t, string(seq.Bytes()), "atgtaa")
+
+	if !scanner.Scan() {
+		t.Fatalf("scanner.Err() = %v", scanner.Err())
+	}
+	testutils.Equals(t, scanner.Value(), gts.Sequence(Fasta{"baz qux", []byte("atg")}))
+
+	if scanner.Scan() {
+		t.Errorf("expected end of input")
+	}
+	if err := scanner.Err(); err != nil {
+		t.Errorf("scanner.Err(): %v", err)
+	}
+
+	scanner = NewAutoScanner(strings.NewReader(`[{"sequence": "atg"}] foo`))
+	if !scanner.Scan() {
+		t.Fatalf("scanner.Err() = %v", scanner.Err())
+	}
+	if scanner.Scan() || scanner.Err() == nil {
+		t.Errorf("expected error after the end of the JSON array")
+	}
+}
+
+var jsonParserFailTests = []string{
+	"",
+	"[",
+	"{",
+	`{"sequence": "atg"`,
+	`{"features": [{"key": "CDS", "location": {"type": "foo", "start": 0, "end": 1}}]}`,
+	`{"features": [{"key": "CDS", "location": {"type": "range"}}]}`,
+	`{"features": [{"key": "CDS", "location": {"type": "range", "start": 0, "end": 1, "strand": "*"}}]}`,
+	`{"fields": {"molecule": "foo"}}`,
+	`]`,
+	`] foo`,
+	`[] foo`,
+	`[,]`,
+}
+
+func TestJSONParserFail(t *testing.T) {
+	for _, in := range jsonParserFailTests {
+		state := pars.FromString(in)
+		parser := pars.AsParser(JSONParser)
+		if _, err := parser.Parse(state); err == nil {
+			t.Errorf("while parsing %q: expected error", in)
+		}
+	}
+}
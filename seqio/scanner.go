@@ -1,6 +1,8 @@
 package seqio
 
 import (
+	"bufio"
+	"bytes"
 	"io"
 
 	"github.com/go-gts/gts"
@@ -9,6 +11,9 @@ import (
 
 var sequenceParsers = []pars.Parser{
 	GenBankParser,
+	EMBLParser,
+	FastqParser,
+	JSONParser,
 	FastaParser,
 }
 
@@ -18,17 +23,31 @@ type Scanner struct {
 	s   *pars.State
 	res pars.Result
 	err error
+
+	// The reader is retained by an automatic scanner to detect GenBank files,
+	// which are then read using the stream.
+	r      *bufio.Reader
+	stream *GenBankStream
 }
 
 // NewScanner creates a new sequence scanner.
 func NewScanner(p pars.Parser, r io.Reader) *Scanner {
-	return &Scanner{p, pars.NewState(r), pars.Result{}, nil}
+	return &Scanner{p, pars.NewState(r), pars.Result{}, nil, nil, nil}
 }
 
 // NewAutoScanner creates a new sequence scanner which will automatically
 // detect the sequence format from a list of known parsers on the first scan.
+// GenBank files are read using a GenBankStream so that the formatted origin of
+// each record is not held in memory.
 func NewAutoScanner(r io.Reader) *Scanner {
-	return NewScanner(nil, r)
+	br := bufio.NewReader(r)
+	return &Scanner{nil, pars.NewState(br), pars.Result{}, nil, br, nil}
+}
+
+// isGenBankInput tests if the buffered input starts with a GenBank LOCUS line.
+func isGenBankInput(r *bufio.Reader) bool {
+	p, _ := r.Peek(512)
+	return bytes.HasPrefix(bytes.TrimLeft(p, " \t\r\n"), []byte("LOCUS"))
 }
 
 // Scan advances the scanner using the given parser. If the parser is not yet
@@ -38,6 +57,16 @@ func (s *Scanner) Scan() bool {
 		return false
 	}
 
+	if s.stream != nil {
+		return s.stream.Scan()
+	}
+
+	if s.p == nil && s.r != nil && isGenBankInput(s.r) {
+		s.p = GenBankParser
+		s.stream = NewGenBankStream(s.r, StreamSequence)
+		return s.stream.Scan()
+	}
+
 	if s.p == nil {
 		errs := make([]struct {
 			err error
@@ -72,6 +101,9 @@ func (s *Scanner) Scan() bool {
 
 // Value returns the most recently scanned sequence value.
 func (s Scanner) Value() gts.Sequence {
+	if s.stream != nil {
+		return s.stream.Value()
+	}
 	if seq, ok := s.res.Value.(gts.Sequence); ok {
 		return seq
 	}
@@ -80,6 +112,9 @@ func (s Scanner) Value() gts.Sequence {
 
 // Err returns the first non-EOF error that was encountered by the scanner.
 func (s Scanner) Err() error {
+	if s.stream != nil {
+		return s.stream.Err()
+	}
 	if s.err == nil || dig(s.err) == io.EOF {
 		return nil
 	}
@@ -107,3 +107,33 @@ func TestAutoScannerFail(t *testing.T) {
 		return
 	}
 }
+
+func TestAutoScannerGenBankStream(t *testing.T) {
+	for _, filename := range genbankStreamTests {
+		in := testutils.ReadTestfile(t, filename)
+		in += in
+		exp := parseGenBankRecords(t, in)
+
+		s := NewAutoScanner(strings.NewReader(in))
+		i := 0
+		for s.Scan() {
+			if s.stream == nil {
+				t.Fatalf("%s: expected the GenBank stream to be used", filename)
+			}
+			seq := s.Value()
+			if _, ok := seq.(GenBank); !ok {
+				t.Errorf("%s: s.Value().(type) = %T, want %T", filename, seq, GenBank{})
+			}
+			testutils.Equals(t, seq.Info(), exp[i].Info())
+			testutils.Equals(t, seq.Features(), exp[i].Features())
+			testutils.Equals(t, seq.Bytes(), exp[i].Bytes())
+			i++
+		}
+		if err := s.Err(); err != nil {
+			t.Errorf("%s: s.Err() = %v", filename, err)
+		}
+		if i != len(exp) {
+			t.Errorf("%s: scanned %d records, want %d", filename, i, len(exp))
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package seqio
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/go-gts/gts"
+	"github.com/go-pars/pars"
+)
+
+// StreamMode represents how a GenBankStream handles the sequence data of the
+// records it reads.
+type StreamMode int
+
+const (
+	// StreamSequence will keep the sequence data of each record. The sequence
+	// is decoded lazily if the underlying reader supports random access.
+	StreamSequence StreamMode = iota
+
+	// StreamFeatures will discard the sequence data of each record, yielding
+	// records with only the metadata and feature table.
+	StreamFeatures
+)
+
+// LazyGenBank represents a GenBank record whose sequence is decoded from the
+// underlying reader only when its bytes are requested for the first time.
+type LazyGenBank struct {
+	Fields GenBankFields
+	Table  gts.FeatureSlice
+	origin *lazyOrigin
+}
+
+// Info returns the metadata of the sequence.
+func (gb LazyGenBank) Info() interface{} {
+	return gb.Fields
+}
+
+// Features returns the feature table of the sequence.
+func (gb LazyGenBank) Features() gts.FeatureSlice {
+	return gb.Table
+}
+
+// Len returns the length of the sequence without decoding it.
+func (gb LazyGenBank) Len() int {
+	return gb.origin.length
+}
+
+// Bytes returns the byte representation of the sequence. The sequence is
+// decoded on the first call and is retained for subsequent calls.
+func (gb LazyGenBank) Bytes() []byte {
+	return gb.origin.Bytes()
+}
+
+// Err returns the error encountered while decoding the sequence, if any.
+func (gb LazyGenBank) Err() error {
+	return gb.origin.err
+}
+
+// WithInfo creates a shallow copy of the given Sequence object and swaps the
+// metadata with the given value.
+func (gb LazyGenBank) WithInfo(info interface{}) gts.Sequence {
+	switch v := info.(type) {
+	case GenBankFields:
+		return LazyGenBank{v, gb.Table, gb.origin}
+	default:
+		return gts.New(v, gb.Features(), gb.Bytes())
+	}
+}
+
+// WithFeatures creates a shallow copy of the given Sequence object and swaps
+// the feature table with the given features.
+func (gb LazyGenBank) WithFeatures(ff []gts.Feature) gts.Sequence {
+	return LazyGenBank{gb.Fields, ff, gb.origin}
+}
+
+// WithBytes creates a shallow copy of the given Sequence object and swaps the
+// byte representation with the given byte slice.
+func (gb LazyGenBank) WithBytes(p []byte) gts.Sequence {
+	return GenBank{gb.Fields, gb.Table, &Origin{p, true}}
+}
+
+// Topology returns the topology of the sequence.
+func (gb LazyGenBank) Topology() gts.Topology {
+	return gb.Fields.Topology
+}
+
+// WithTopology creates a shallow copy of the given Sequence object and swaps
+// the topology value with the given value.
+func (gb LazyGenBank) WithTopology(t gts.Topology) gts.Sequence {
+	info := gb.Fields
+	info.Topology = t
+	return gb.WithInfo(info)
+}
+
+type lazyOrigin struct {
+	r      io.ReaderAt
+	offset int64
+	size   int64
+	length int
+	p      []byte
+	err    error
+}
+
+func (o *lazyOrigin) Bytes() []byte {
+	if o.p == nil && o.err == nil {
+		p := make([]byte, 0, o.length)
+		r := io.NewSectionReader(o.r, o.offset, o.size)
+		buf := make([]byte, 64*1024)
+		for {
+			n, err := r.Read(buf)
+			p = appendOriginBases(p, buf[:n])
+			if err != nil {
+				if err != io.EOF {
+					o.err = err
+				}
+				break
+			}
+		}
+		if o.err == nil && len(p) != o.length {
+			o.err = errors.New("sequence changed since the record was read")
+		}
+		o.p = p
+	}
+	return o.p
+}
+
+func isOriginBase(c byte) bool {
+	return !(c <= ' ' || ('0' <= c && c <= '9'))
+}
+
+func appendOriginBases(p, line []byte) []byte {
+	for _, c := range line {
+		if isOriginBase(c) {
+			p = append(p, c)
+		}
+	}
+	return p
+}
+
+func countOriginBases(line []byte) int {
+	n := 0
+	for _, c := range line {
+		if isOriginBase(c) {
+			n++
+		}
+	}
+	return n
+}
+
+// locusLength extracts the sequence length from a GenBank LOCUS line.
+func locusLength(line []byte) int {
+	fields := bytes.Fields(line)
+	if len(fields) < 3 {
+		return 0
+	}
+	n, err := strconv.Atoi(string(fields[2]))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// GenBankStream reads GenBank records from a reader one at a time while only
+// retaining the metadata and feature table of the current record. Unlike a
+// Scanner using GenBankParser, the formatted origin of a record is never held
+// in memory. If the reader implements io.ReaderAt and io.Seeker, as a regular
+// *os.File does, the records are yielded as LazyGenBank values which decode
+// the sequence from the reader on demand. Otherwise, the sequence is decoded
+// as the record is read, unless the stream is in the StreamFeatures mode.
+type GenBankStream struct {
+	r      *bufio.Reader
+	ra     io.ReaderAt
+	offset int64
+	mode   StreamMode
+	seq    gts.Sequence
+	err    error
+}
+
+// NewGenBankStream creates a new GenBank stream.
+func NewGenBankStream(r io.Reader, mode StreamMode) *GenBankStream {
+	s := &GenBankStream{r: bufio.NewReader(r), mode: mode}
+	if ra, ok := r.(io.ReaderAt); ok {
+		if seeker, ok := r.(io.Seeker); ok {
+			if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+				s.ra, s.offset = ra, offset
+			}
+		}
+	}
+	return s
+}
+
+func (s *GenBankStream) readLine() ([]byte, error) {
+	line, err := s.r.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		buf := append([]byte(nil), line...)
+		for err == bufio.ErrBufferFull {
+			line, err = s.r.ReadSlice('\n')
+			buf = append(buf, line...)
+		}
+		line = buf
+	}
+	s.offset += int64(len(line))
+	if err == io.EOF && len(line) > 0 {
+		err = nil
+	}
+	return line, err
+}
+
+// Scan advances the stream to the next record.
+func (s *GenBankStream) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+	s.seq = nil
+
+	head := []byte{}
+	origin := false
+	for !origin {
+		line, err := s.readLine()
+		if err != nil {
+			if err == io.EOF && len(head) > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			s.err = err
+			return false
+		}
+		if len(head) == 0 && len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if bytes.HasPrefix(line, []byte("//")) {
+			break
+		}
+		if bytes.HasPrefix(line, []byte("ORIGIN")) {
+			origin = true
+			continue
+		}
+		head = append(head, line...)
+	}
+
+	length := locusLength(head[:bytes.IndexByte(head, '\n')+1])
+	head = append(head, "//\n"...)
+	result, err := pars.AsParser(GenBankParser).Parse(pars.NewState(bytes.NewReader(head)))
+	if err != nil {
+		s.err = err
+		return false
+	}
+	gb := result.Value.(GenBank)
+
+	if !origin {
+		s.seq = gb
+		return true
+	}
+
+	lazy := s.ra != nil && s.mode == StreamSequence
+	offset, size, n := s.offset, int64(0), 0
+
+	var p []byte
+	if !lazy && s.mode == StreamSequence {
+		p = make([]byte, 0, length)
+	}
+
+	for {
+		line, err := s.readLine()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			s.err = err
+			return false
+		}
+		if bytes.HasPrefix(line, []byte("//")) {
+			break
+		}
+		size += int64(len(line))
+		if p != nil {
+			p = appendOriginBases(p, line)
+		} else {
+			n += countOriginBases(line)
+		}
+	}
+
+	if p != nil {
+		n = len(p)
+	}
+	if n != length {
+		s.err = fmt.Errorf("origin of %q has %d bases, expected %d", gb.Fields.LocusName, n, length)
+		return false
+	}
+
+	switch {
+	case lazy:
+		s.seq = LazyGenBank{gb.Fields, gb.Table, &lazyOrigin{s.ra, offset, size, n, nil, nil}}
+	case p != nil:
+		s.seq = GenBank{gb.Fields, gb.Table, &Origin{p, true}}
+	default:
+		s.seq = gb
+	}
+
+	return true
+}
+
+// Value returns the most recently scanned sequence value.
+func (s GenBankStream) Value() gts.Sequence {
+	return s.seq
+}
+
+// Err returns the first non-EOF error that was encountered by the stream.
+func (s GenBankStream) Err() error {
+	if s.err == nil || s.err == io.EOF {
+		return nil
+	}
+	return s.err
+}
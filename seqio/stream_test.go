@@ -0,0 +1,242 @@
+package seqio
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/internal/testutils"
+	"github.com/go-pars/pars"
+)
+
+// sequentialReader hides the io.ReaderAt and io.Seeker implementations of the
+// underlying reader.
+type sequentialReader struct {
+	io.Reader
+}
+
+var genbankStreamTests = []string{
+	"NC_001422.gb",
+	"NC_001422_part.gb",
+	"NC_000913.3.min.gb",
+}
+
+func parseGenBankRecords(t *testing.T, in string) []gts.Sequence {
+	t.Helper()
+	seqs := []gts.Sequence{}
+	scanner := NewScanner(GenBankParser, strings.NewReader(in))
+	for scanner.Scan() {
+		seqs = append(seqs, scanner.Value())
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("while parsing GenBank records: %v", err)
+	}
+	return seqs
+}
+
+func TestGenBankStream(t *testing.T) {
+	for _, filename := range genbankStreamTests {
+		in := testutils.ReadTestfile(t, filename)
+		in += in
+		exp := parseGenBankRecords(t, in)
+
+		readers := []struct {
+			name string
+			r    io.Reader
+			lazy bool
+		}{
+			{"random access", strings.NewReader(in), true},
+			{"sequential", sequentialReader{strings.NewReader(in)}, false},
+		}
+
+		for _, tt := range readers {
+			stream := NewGenBankStream(tt.r, StreamSequence)
+			i := 0
+			for stream.Scan() {
+				seq := stream.Value()
+				if i >= len(exp) {
+					t.Fatalf("%s (%s): too many records", filename, tt.name)
+				}
+				if _, ok := seq.(LazyGenBank); ok != (tt.lazy && gts.Len(exp[i]) > 0) {
+					t.Errorf("%s (%s): got %T", filename, tt.name, seq)
+				}
+				testutils.Equals(t, seq.Info(), exp[i].Info())
+				testutils.Equals(t, seq.Features(), exp[i].Features())
+				if gts.Len(seq) != gts.Len(exp[i]) {
+					t.Errorf("%s (%s): gts.Len(seq) = %d, want %d", filename, tt.name, gts.Len(seq), gts.Len(exp[i]))
+				}
+				if !bytes.Equal(seq.Bytes(), exp[i].Bytes()) {
+					t.Errorf("%s (%s): sequence of record %d differs", filename, tt.name, i)
+				}
+				i++
+			}
+			if err := stream.Err(); err != nil {
+				t.Errorf("%s (%s): stream.Err() = %v", filename, tt.name, err)
+			}
+			if i != len(exp) {
+				t.Errorf("%s (%s): got %d records, want %d", filename, tt.name, i, len(exp))
+			}
+		}
+	}
+}
+
+func TestGenBankStreamOffset(t *testing.T) {
+	in := testutils.ReadTestfile(t, "NC_001422.gb")
+	exp := parseGenBankRecords(t, in)[0]
+
+	r := strings.NewReader("\n\n" + in)
+	r.Seek(2, io.SeekStart)
+	stream := NewGenBankStream(r, StreamSequence)
+	if !stream.Scan() {
+		t.Fatalf("stream.Scan() = false: %v", stream.Err())
+	}
+	seq := stream.Value().(LazyGenBank)
+	if !bytes.Equal(seq.Bytes(), exp.Bytes()) || seq.Err() != nil {
+		t.Errorf("lazily decoded sequence differs: %v", seq.Err())
+	}
+
+	formatGenBankHelper(t, seq, in)
+}
+
+func TestGenBankStreamFeatures(t *testing.T) {
+	in := testutils.ReadTestfile(t, "NC_001422.gb")
+	exp := parseGenBankRecords(t, in)[0]
+
+	for _, r := range []io.Reader{strings.NewReader(in), sequentialReader{strings.NewReader(in)}} {
+		stream := NewGenBankStream(r, StreamFeatures)
+		if !stream.Scan() {
+			t.Fatalf("stream.Scan() = false: %v", stream.Err())
+		}
+		seq := stream.Value()
+		testutils.Equals(t, seq.Features(), exp.Features())
+		if len(seq.Bytes()) != 0 {
+			t.Errorf("len(seq.Bytes()) = %d, want 0", len(seq.Bytes()))
+		}
+		if stream.Scan() || stream.Err() != nil {
+			t.Errorf("expected end of stream, got error: %v", stream.Err())
+		}
+	}
+}
+
+func TestGenBankStreamFail(t *testing.T) {
+	in := testutils.ReadTestfile(t, "NC_001422.gb")
+	origin := strings.Index(in, "ORIGIN")
+
+	tests := []string{
+		in[:origin],
+		in[:len(in)-100],
+		strings.Replace(in, "5386 bp", "5387 bp", 1),
+		strings.Replace(in, "LOCUS", "LOCAS", 1),
+	}
+
+	for _, in := range tests {
+		for _, mode := range []StreamMode{StreamSequence, StreamFeatures} {
+			stream := NewGenBankStream(strings.NewReader(in), mode)
+			if stream.Scan() || stream.Err() == nil {
+				t.Errorf("expected error while streaming:\n%s", in[:80])
+			}
+		}
+	}
+}
+
+func TestLazyGenBank(t *testing.T) {
+	in := testutils.ReadTestfile(t, "NC_001422.gb")
+	stream := NewGenBankStream(strings.NewReader(in), StreamSequence)
+	stream.Scan()
+	seq := stream.Value().(LazyGenBank)
+
+	info := seq.Fields
+	info.LocusName = "NEW_LOCUS"
+	if v := seq.WithInfo(info).(LazyGenBank); v.Fields.LocusName != "NEW_LOCUS" {
+		t.Errorf("seq.WithInfo(info).Info() = %v", v.Info())
+	}
+
+	if v := seq.WithInfo("info"); v.Info() != "info" || !bytes.Equal(v.Bytes(), seq.Bytes()) {
+		t.Errorf("seq.WithInfo(%q) = %v", "info", v)
+	}
+
+	if v := seq.WithFeatures(nil); len(v.Features()) != 0 || v.(LazyGenBank).origin != seq.origin {
+		t.Errorf("seq.WithFeatures(nil) = %v", v)
+	}
+
+	p := []byte("atgc")
+	if v := seq.WithBytes(p); !reflect.DeepEqual(v.Bytes(), p) || gts.Len(v) != len(p) {
+		t.Errorf("seq.WithBytes(%q).Bytes() = %q", p, v.Bytes())
+	}
+
+	if v := seq.WithTopology(gts.Linear); gts.TopologyOf(v) != gts.Linear {
+		t.Errorf("seq.WithTopology(gts.Linear).Topology() != gts.Linear")
+	}
+}
+
+func makeGenBankBenchmarkInput(b *testing.B) []byte {
+	b.Helper()
+	p, err := ioutil.ReadFile(filepath.Join("testdata", "NC_001422.gb"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	state := pars.FromBytes(p)
+	result, err := pars.AsParser(GenBankParser).Parse(state)
+	if err != nil {
+		b.Fatal(err)
+	}
+	seq := result.Value.(GenBank)
+	seq = seq.WithBytes(bytes.Repeat(seq.Bytes(), 200)).(GenBank)
+
+	buf := bytes.Buffer{}
+	for i := 0; i < 4; i++ {
+		if _, err := (GenBankWriter{&buf}).WriteSeq(seq); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func BenchmarkGenBankParser(b *testing.B) {
+	in := makeGenBankBenchmarkInput(b)
+	b.SetBytes(int64(len(in)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scanner := NewScanner(GenBankParser, bytes.NewReader(in))
+		for scanner.Scan() {
+			scanner.Value().Bytes()
+		}
+		if err := scanner.Err(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkGenBankStream(b *testing.B, mode StreamMode, wrap func(io.Reader) io.Reader) {
+	in := makeGenBankBenchmarkInput(b)
+	b.SetBytes(int64(len(in)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stream := NewGenBankStream(wrap(bytes.NewReader(in)), mode)
+		for stream.Scan() {
+			stream.Value().Bytes()
+		}
+		if err := stream.Err(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGenBankStreamLazy(b *testing.B) {
+	benchmarkGenBankStream(b, StreamSequence, func(r io.Reader) io.Reader { return r })
+}
+
+func BenchmarkGenBankStreamSequential(b *testing.B) {
+	benchmarkGenBankStream(b, StreamSequence, func(r io.Reader) io.Reader { return sequentialReader{r} })
+}
+
+func BenchmarkGenBankStreamFeatures(b *testing.B) {
+	benchmarkGenBankStream(b, StreamFeatures, func(r io.Reader) io.Reader { return sequentialReader{r} })
+}
@@ -0,0 +1,24 @@
+package seqio
+
+import "github.com/go-gts/gts"
+
+// FeatureEntry represents the features associated to a single sequence ID in
+// a feature annotation file such as GFF3 or BED.
+type FeatureEntry struct {
+	SeqID    string
+	Features gts.FeatureSlice
+}
+
+// FeatureTable represents the contents of a feature annotation file as a list
+// of entries in the order of appearance.
+type FeatureTable []FeatureEntry
+
+// Lookup the features associated to the given sequence ID.
+func (table FeatureTable) Lookup(id string) (gts.FeatureSlice, bool) {
+	for _, entry := range table {
+		if entry.SeqID == id {
+			return entry.Features, true
+		}
+	}
+	return nil, false
+}
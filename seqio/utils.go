@@ -1,8 +1,69 @@
 package seqio
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-gts/gts"
+)
+
 func dig(err error) error {
 	if v, ok := err.(interface{ Unwrap() error }); ok {
 		return dig(v.Unwrap())
 	}
 	return err
 }
+
+// SequenceID returns the identifier of the given sequence. If the metadata
+// does not provide an ID, the first word of its string representation is used.
+func SequenceID(seq gts.Sequence) string {
+	switch info := seq.Info().(type) {
+	case interface{ ID() string }:
+		return info.ID()
+	case string:
+		if fields := strings.Fields(info); len(fields) > 0 {
+			return fields[0]
+		}
+	case fmt.Stringer:
+		if fields := strings.Fields(info.String()); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+// remoteAccessions returns the accessions of the remote parts of the given
+// location, which cannot be represented in formats without remote locations.
+func remoteAccessions(loc gts.Location) []string {
+	switch v := loc.(type) {
+	case gts.Remote:
+		return []string{v.Accession}
+	case gts.Complemented:
+		return remoteAccessions(v.Location)
+	case gts.Joined:
+		return remoteAccessionsList(v)
+	case gts.Ordered:
+		return remoteAccessionsList(v)
+	default:
+		return nil
+	}
+}
+
+func remoteAccessionsList(locs []gts.Location) []string {
+	ret := []string{}
+	for _, loc := range locs {
+		ret = append(ret, remoteAccessions(loc)...)
+	}
+	return ret
+}
+
+// formatRemoteNote formats a comment line noting the remote parts of the
+// feature which were not written, or that the whole feature was skipped if
+// it only consists of remote parts.
+func formatRemoteNote(chrom string, f gts.Feature, skipped bool) string {
+	accessions := strings.Join(remoteAccessions(f.Loc), ", ")
+	if skipped {
+		return fmt.Sprintf("# skipped %s %s in %s: remote location in %s\n", f.Key, f.Loc, chrom, accessions)
+	}
+	return fmt.Sprintf("# omitted remote parts of %s %s in %s: %s\n", f.Key, f.Loc, chrom, accessions)
+}
@@ -0,0 +1,126 @@
+package seqio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-gts/gts"
+	"github.com/go-pars/pars"
+)
+
+// VariantEntry represents the variants associated to a single sequence ID in
+// a variant call file.
+type VariantEntry struct {
+	SeqID    string
+	Variants []gts.Variant
+}
+
+// VariantTable represents the contents of a variant call file as a list of
+// entries in the order of appearance.
+type VariantTable []VariantEntry
+
+// Lookup the variants associated to the given sequence ID.
+func (table VariantTable) Lookup(id string) ([]gts.Variant, bool) {
+	for _, entry := range table {
+		if entry.SeqID == id {
+			return entry.Variants, true
+		}
+	}
+	return nil, false
+}
+
+func isVCFAllele(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("ACGTNacgtn", c) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseVCFLine(line string, pos pars.Position) (string, gts.Variant, bool, error) {
+	cols := strings.Split(line, "\t")
+	if len(cols) < 5 {
+		what := fmt.Sprintf("expected at least 5 columns in VCF line, got %d", len(cols))
+		return "", gts.Variant{}, false, pars.NewError(what, pos)
+	}
+
+	n, err := strconv.Atoi(cols[1])
+	if err != nil || n < 1 {
+		return "", gts.Variant{}, false, pars.NewError("expected positive integer in POS column", pos)
+	}
+
+	ref := cols[3]
+	if !isVCFAllele(ref) {
+		what := fmt.Sprintf("unsupported reference allele %q", ref)
+		return "", gts.Variant{}, false, pars.NewError(what, pos)
+	}
+
+	// Only the first alternate allele is used. Missing and symbolic alleles
+	// are skipped as they cannot be applied to a sequence.
+	alt := strings.Split(cols[4], ",")[0]
+	if !isVCFAllele(alt) {
+		return cols[0], gts.Variant{}, false, nil
+	}
+
+	v := gts.Variant{Pos: n - 1, Ref: []byte(ref), Alt: []byte(alt), ID: cols[2]}
+	return cols[0], v, true, nil
+}
+
+// VCFParser attempts to parse the data lines of a VCF file. Meta-information
+// and header lines starting with `#` are ignored. Only the first alternate
+// allele of each record is used, and records with missing or symbolic
+// alternate alleles are skipped.
+func VCFParser(state *pars.State, result *pars.Result) error {
+	table := VariantTable{}
+	index := make(map[string]int)
+	header := false
+
+	for state.Request(1) == nil {
+		pos := state.Position()
+		pars.Line(state, result)
+		line := strings.TrimRight(string(result.Token), "\r")
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			continue
+		case strings.HasPrefix(line, "#CHROM"):
+			header = true
+			continue
+		case strings.HasPrefix(line, "#"):
+			continue
+		}
+
+		if !header {
+			return pars.NewError("expected VCF header line", pos)
+		}
+
+		chrom, v, ok, err := parseVCFLine(line, pos)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		i, ok := index[chrom]
+		if !ok {
+			i = len(table)
+			index[chrom] = i
+			table = append(table, VariantEntry{chrom, nil})
+		}
+		table[i].Variants = append(table[i].Variants, v)
+	}
+
+	if !header {
+		return pars.NewError("expected VCF header line", state.Position())
+	}
+
+	state.Clear()
+	result.SetValue(table)
+	return nil
+}
@@ -0,0 +1,75 @@
+package seqio
+
+import (
+	"testing"
+
+	"github.com/go-gts/gts"
+	"github.com/go-gts/gts/internal/testutils"
+	"github.com/go-pars/pars"
+)
+
+func TestVCFParser(t *testing.T) {
+	in := "" +
+		"##fileformat=VCFv4.2\n" +
+		"##contig=<ID=chr1,length=100>\n" +
+		"#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO\n" +
+		"chr1\t10\trs1\tA\tG,T\t50\tPASS\t.\n" +
+		"chr1\t20\t.\tAC\tA\t50\tPASS\t.\n" +
+		"chr1\t30\t.\tA\t<DEL>\t50\tPASS\tSVTYPE=DEL\n" +
+		"chr1\t40\t.\tA\t.\t50\tPASS\t.\n" +
+		"chr2\t5\t.\tG\tGTT\t50\tPASS\t.\n"
+
+	state := pars.FromString(in)
+	result, err := pars.AsParser(VCFParser).Parse(state)
+	if err != nil {
+		t.Errorf("parser returned %v", err)
+		return
+	}
+
+	table := result.Value.(VariantTable)
+	if len(table) != 2 {
+		t.Errorf("len(table) = %d, want 2", len(table))
+		return
+	}
+
+	vv, ok := table.Lookup("chr1")
+	if !ok {
+		t.Errorf("table.Lookup(%q) = (%v, %t)", "chr1", vv, ok)
+		return
+	}
+	testutils.Equals(t, vv, []gts.Variant{
+		{Pos: 9, Ref: []byte("A"), Alt: []byte("G"), ID: "rs1"},
+		{Pos: 19, Ref: []byte("AC"), Alt: []byte("A"), ID: "."},
+	})
+
+	vv, ok = table.Lookup("chr2")
+	if !ok {
+		t.Errorf("table.Lookup(%q) = (%v, %t)", "chr2", vv, ok)
+		return
+	}
+	testutils.Equals(t, vv, []gts.Variant{
+		{Pos: 4, Ref: []byte("G"), Alt: []byte("GTT"), ID: "."},
+	})
+
+	if _, ok := table.Lookup("chr3"); ok {
+		t.Errorf("table.Lookup(%q) should fail", "chr3")
+	}
+}
+
+var vcfParserFailTests = []string{
+	"",
+	"chr1\t10\t.\tA\tG\n",
+	"#CHROM\tPOS\tID\tREF\tALT\nchr1\t10\t.\tA\n",
+	"#CHROM\tPOS\tID\tREF\tALT\nchr1\tten\t.\tA\tG\n",
+	"#CHROM\tPOS\tID\tREF\tALT\nchr1\t0\t.\tA\tG\n",
+	"#CHROM\tPOS\tID\tREF\tALT\nchr1\t10\t.\t.\tG\n",
+}
+
+func TestVCFParserFail(t *testing.T) {
+	for _, in := range vcfParserFailTests {
+		state := pars.FromString(in)
+		if _, err := pars.AsParser(VCFParser).Parse(state); err == nil {
+			t.Errorf("expected error while parsing %q", in)
+		}
+	}
+}
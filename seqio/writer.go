@@ -20,8 +20,20 @@ func NewWriter(w io.Writer, filetype FileType) SeqWriter {
 	switch filetype {
 	case FastaFile:
 		return FastaWriter{w}
+	case FastqFile:
+		return FastqWriter{w}
 	case GenBankFile:
 		return GenBankWriter{w}
+	case EMBLFile:
+		return EMBLWriter{w}
+	case GFF3File:
+		return NewGFF3Writer(w)
+	case BEDFile:
+		return BEDWriter{w}
+	case JSONFile:
+		return NewJSONWriter(w, false)
+	case JSONLinesFile:
+		return NewJSONWriter(w, true)
 	default:
 		return AutoWriter{w, nil}
 	}
@@ -31,12 +43,18 @@ func detectWriter(seq gts.Sequence, w io.Writer) (SeqWriter, error) {
 	switch seq.(type) {
 	case GenBank, *GenBank:
 		return GenBankWriter{w}, nil
+	case EMBL, *EMBL:
+		return EMBLWriter{w}, nil
 	case Fasta, *Fasta:
 		return FastaWriter{w}, nil
+	case Fastq, *Fastq:
+		return FastqWriter{w}, nil
 	default:
 		switch info := seq.Info().(type) {
 		case GenBankFields:
 			return GenBankWriter{w}, nil
+		case FastqFields:
+			return FastqWriter{w}, nil
 		case string, fmt.Stringer:
 			return FastaWriter{w}, nil
 		default:
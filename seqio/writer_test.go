@@ -16,6 +16,9 @@ var writerTests = []struct {
 	{"NC_001422.fasta", DefaultFile},
 	{"NC_001422.gb", GenBankFile},
 	{"NC_001422.gb", DefaultFile},
+	{"NC_001422.embl", EMBLFile},
+	{"SRR001666_1.fastq", FastqFile},
+	{"SRR001666_1.fastq", DefaultFile},
 }
 
 func TestWriter(t *testing.T) {
@@ -24,6 +24,16 @@ type Sliceable interface {
 	Slice(start, end int) interface{}
 }
 
+// Reversible represents a reversible metadata.
+type Reversible interface {
+	Reverse(length int) interface{}
+}
+
+// Rotatable represents a rotatable metadata.
+type Rotatable interface {
+	Rotate(n int) interface{}
+}
+
 func tryShift(info interface{}, i, n int) interface{} {
 	if v, ok := info.(Shiftable); ok {
 		return v.Shift(i, n)
@@ -45,6 +55,20 @@ func trySlice(info interface{}, start, end int) interface{} {
 	return info
 }
 
+func tryReverse(info interface{}, length int) interface{} {
+	if v, ok := info.(Reversible); ok {
+		return v.Reverse(length)
+	}
+	return info
+}
+
+func tryRotate(info interface{}, n int) interface{} {
+	if v, ok := info.(Rotatable); ok {
+		return v.Rotate(n)
+	}
+	return info
+}
+
 // Sequence represents a biological sequence. All sequences are expected to be
 // able to return its metadata, associated features, and byte representation.
 type Sequence interface {
@@ -167,11 +191,15 @@ func Insert(host Sequence, index int, guest Sequence) Sequence {
 
 	var ff FeatureSlice
 	for _, f := range host.Features() {
-		f.Loc = f.Loc.Shift(index, Len(guest))
+		f = mapFeatureLocations(f, func(loc Location) Location {
+			return loc.Shift(index, Len(guest))
+		})
 		ff = ff.Insert(f)
 	}
 	for _, f := range guest.Features() {
-		f.Loc = f.Loc.Expand(0, index)
+		f = mapFeatureLocations(f, func(loc Location) Location {
+			return loc.Expand(0, index)
+		})
 		ff = ff.Insert(f)
 	}
 	host = WithFeatures(host, ff)
@@ -192,11 +220,15 @@ func Embed(host Sequence, index int, guest Sequence) Sequence {
 
 	var ff FeatureSlice
 	for _, f := range host.Features() {
-		f.Loc = f.Loc.Expand(index, Len(guest))
+		f = mapFeatureLocations(f, func(loc Location) Location {
+			return loc.Expand(index, Len(guest))
+		})
 		ff = ff.Insert(f)
 	}
 	for _, f := range guest.Features() {
-		f.Loc = f.Loc.Expand(0, index)
+		f = mapFeatureLocations(f, func(loc Location) Location {
+			return loc.Expand(0, index)
+		})
 		ff = ff.Insert(f)
 	}
 	host = WithFeatures(host, ff)
@@ -219,7 +251,9 @@ func Delete(seq Sequence, offset, length int) Sequence {
 
 	ff := seq.Features()
 	for i, f := range ff {
-		ff[i].Loc = f.Loc.Expand(offset, -length)
+		ff[i] = mapFeatureLocations(f, func(loc Location) Location {
+			return loc.Expand(offset, -length)
+		})
 	}
 	seq = WithFeatures(seq, ff)
 
@@ -237,8 +271,13 @@ func Delete(seq Sequence, offset, length int) Sequence {
 // shortened by the length of deletion. If the entirety of the feature is
 // shortened as a result, the location will be removed from the sequence.
 func Erase(seq Sequence, offset, length int) Sequence {
-	f := Or(Key("source"), Not(Within(offset, offset+length)))
-	ff := seq.Features().Filter(f)
+	var ff FeatureSlice
+	if v, ok := seq.(hasFeatureIndex); ok {
+		ff = v.FeatureIndex().erase(offset, offset+length)
+	} else {
+		f := Or(Key("source"), Not(Within(offset, offset+length)))
+		ff = seq.Features().Filter(f)
+	}
 	seq = WithFeatures(seq, ff)
 	return Delete(seq, offset, length)
 }
@@ -265,14 +304,21 @@ func Slice(seq Sequence, start, end int) Sequence {
 	info := seq.Info()
 	info = trySlice(info, start, end)
 
-	ff := seq.Features().Filter(Overlap(start, end))
+	var ff FeatureSlice
+	if v, ok := seq.(hasFeatureIndex); ok {
+		ff = v.FeatureIndex().Overlap(start, end)
+	} else {
+		ff = seq.Features().Filter(Overlap(start, end))
+	}
 
 	for i, f := range ff {
-		loc := f.Loc.Expand(end, end-seqlen).Expand(0, -start)
+		f = mapFeatureLocations(f, func(loc Location) Location {
+			return loc.Expand(end, end-seqlen).Expand(0, -start)
+		})
 		if f.Key == "source" {
-			loc = asComplete(loc)
+			f.Loc = asComplete(f.Loc)
 		}
-		ff[i].Loc = loc
+		ff[i] = f
 	}
 
 	p := make([]byte, end-start)
@@ -300,7 +346,9 @@ func Concat(ss ...Sequence) Sequence {
 
 		for _, seq := range tail {
 			for _, f := range seq.Features() {
-				f.Loc = f.Loc.Expand(0, len(p))
+				f = mapFeatureLocations(f, func(loc Location) Location {
+					return loc.Expand(0, len(p))
+				})
 				ff = ff.Insert(f)
 			}
 			p = append(p, seq.Bytes()...)
@@ -316,9 +364,16 @@ func Concat(ss ...Sequence) Sequence {
 // Reverse returns a Sequence object with the byte representation in the
 // reversed order. The feature locations will be reversed accordingly.
 func Reverse(seq Sequence) Sequence {
+	info := seq.Info()
+	info = tryReverse(info, Len(seq))
+	seq = WithInfo(seq, info)
+
 	var ff FeatureSlice
 	for _, f := range seq.Features() {
-		ff = ff.Insert(Feature{f.Key, f.Loc.Reverse(Len(seq)), f.Props.Clone()})
+		f = mapFeatureLocations(f, func(loc Location) Location {
+			return loc.Reverse(Len(seq))
+		})
+		ff = ff.Insert(Feature{f.Key, f.Loc, f.Props.Clone()})
 	}
 	seq = WithFeatures(seq, ff)
 
@@ -339,9 +394,15 @@ func Rotate(seq Sequence, n int) Sequence {
 	}
 	n %= Len(seq)
 
+	info := seq.Info()
+	info = tryRotate(info, n)
+	seq = WithInfo(seq, info)
+
 	var ff FeatureSlice
 	for _, f := range seq.Features() {
-		f.Loc = f.Loc.Expand(0, n).Normalize(Len(seq))
+		f = mapFeatureLocations(f, func(loc Location) Location {
+			return loc.Expand(0, n).Normalize(Len(seq))
+		})
 		ff = ff.Insert(f)
 	}
 
@@ -360,19 +421,23 @@ func bytesIndexAll(s, sep []byte) []int {
 	return index.Lookup(sep, -1)
 }
 
-// Search for a subsequence within a sequence.
+// Search for a subsequence within a sequence. Matches spanning across the
+// origin of a circular sequence are reported in the same manner as Match.
 func Search(seq Sequence, query Sequence) []Segment {
 	if Len(seq) == 0 || Len(query) == 0 {
 		return nil
 	}
 
-	s := bytes.ToLower(seq.Bytes())
+	s := bytes.ToLower(searchBytes(seq, Len(query)-1))
 	sep := bytes.ToLower(query.Bytes())
 
 	indices := bytesIndexAll(s, sep)
-	segments := make([]Segment, len(indices))
-	for i, index := range indices {
-		segments[i] = Segment{index, index + len(sep)}
+	segments := make([]Segment, 0, len(indices))
+	for _, index := range indices {
+		segment := Segment{index, index + len(sep)}
+		if withinCircle(segment, Len(seq)) {
+			segments = append(segments, segment)
+		}
 	}
 	sort.Sort(BySegment(segments))
 	return segments
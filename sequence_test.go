@@ -36,6 +36,14 @@ func (info infoInterface) Slice(start, end int) interface{} {
 	return infoInterface{start, end}
 }
 
+func (info infoInterface) Reverse(length int) interface{} {
+	return infoInterface{length, 0}
+}
+
+func (info infoInterface) Rotate(n int) interface{} {
+	return infoInterface{0, n}
+}
+
 func TestShiftableExpandable(t *testing.T) {
 	var in, out interface{}
 	i, n := 3, 6
@@ -78,6 +86,35 @@ func TestShiftableExpandable(t *testing.T) {
 	}
 }
 
+func TestReversibleRotatable(t *testing.T) {
+	var in, out interface{}
+	n := 6
+
+	in = infoInterface{0, 0}
+	out = tryReverse(in, n)
+	if exp := (infoInterface{n, 0}); !reflect.DeepEqual(out, exp) {
+		t.Errorf("tryReverse(%v, %d) = %v, want %v", in, n, out, exp)
+	}
+
+	in = infoInterface{0, 0}
+	out = tryRotate(in, n)
+	if exp := (infoInterface{0, n}); !reflect.DeepEqual(out, exp) {
+		t.Errorf("tryRotate(%v, %d) = %v, want %v", in, n, out, exp)
+	}
+
+	in = "info"
+	out = tryReverse(in, n)
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("tryReverse(%v, %d) = %v, want %v", in, n, out, in)
+	}
+
+	in = "info"
+	out = tryRotate(in, n)
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("tryRotate(%v, %d) = %v, want %v", in, n, out, in)
+	}
+}
+
 type LenObj []byte
 
 func (obj LenObj) Info() interface{} {
@@ -482,3 +519,16 @@ func TestSearch(t *testing.T) {
 		}
 	}
 }
+
+func TestSearchCircular(t *testing.T) {
+	p := []byte("atgcatgcatgc")
+	query := New(nil, nil, []byte("gcat"))
+
+	linear := newSeqWithTest(Linear, nil, p)
+	exp := []Segment{{2, 6}, {6, 10}}
+	testutils.Equals(t, Search(linear, query), exp)
+
+	circular := newSeqWithTest(Circular, nil, p)
+	exp = []Segment{{2, 6}, {6, 10}, {10, 14}}
+	testutils.Equals(t, Search(circular, query), exp)
+}
@@ -55,3 +55,17 @@ func WithTopology(seq Sequence, t Topology) Sequence {
 		return seq
 	}
 }
+
+type hasTopology interface {
+	Topology() Topology
+}
+
+// TopologyOf returns the topology of the given Sequence object. If the
+// sequence implements the `Topology() Topology` method, it will be called.
+// Otherwise, the sequence is considered to be linear.
+func TopologyOf(seq Sequence) Topology {
+	if v, ok := seq.(hasTopology); ok {
+		return v.Topology()
+	}
+	return Linear
+}
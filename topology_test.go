@@ -57,3 +57,29 @@ func TestWithTopology(t *testing.T) {
 		testutils.Equals(t, out, tt.out)
 	}
 }
+
+func (wt seqWithTest) Topology() Topology {
+	if t, ok := wt.info.(Topology); ok {
+		return t
+	}
+	return Linear
+}
+
+var topologyOfTests = []struct {
+	in  Sequence
+	out Topology
+}{
+	{New(nil, nil, nil), Linear},
+	{newSeqWithTest(nil, nil, nil), Linear},
+	{newSeqWithTest(Linear, nil, nil), Linear},
+	{newSeqWithTest(Circular, nil, nil), Circular},
+}
+
+func TestTopologyOf(t *testing.T) {
+	for _, tt := range topologyOfTests {
+		out := TopologyOf(tt.in)
+		if out != tt.out {
+			t.Errorf("TopologyOf(%v) = %v, want %v", tt.in, out, tt.out)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package gts
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// GeneticCode represents an NCBI genetic code table. The amino acids and
+// start codons are represented as strings of 64 characters in the order used
+// by NCBI, where the bases of each codon run in the order of `TCAG`.
+type GeneticCode struct {
+	ID         int
+	Name       string
+	AminoAcids string
+	Starts     string
+}
+
+// GeneticCodes contains the genetic code tables defined by NCBI.
+// See https://www.ncbi.nlm.nih.gov/Taxonomy/Utils/wprintgc.cgi for details.
+var GeneticCodes = map[int]GeneticCode{
+	1: {
+		1, "Standard",
+		"FFLLSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"---M---------------M---------------M----------------------------",
+	},
+	2: {
+		2, "Vertebrate Mitochondrial",
+		"FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNKKSS**VVVVAAAADDEEGGGG",
+		"--------------------------------MMMM---------------M------------",
+	},
+	3: {
+		3, "Yeast Mitochondrial",
+		"FFLLSSSSYY**CCWWTTTTPPPPHHQQRRRRIIMMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"----------------------------------MM----------------------------",
+	},
+	4: {
+		4, "Mold, Protozoan, and Coelenterate Mitochondrial and Mycoplasma/Spiroplasma",
+		"FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"--MM---------------M------------MMMM---------------M------------",
+	},
+	5: {
+		5, "Invertebrate Mitochondrial",
+		"FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNKKSSSSVVVVAAAADDEEGGGG",
+		"---M----------------------------MMMM---------------M------------",
+	},
+	6: {
+		6, "Ciliate, Dasycladacean and Hexamita Nuclear",
+		"FFLLSSSSYYQQCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"-----------------------------------M----------------------------",
+	},
+	9: {
+		9, "Echinoderm and Flatworm Mitochondrial",
+		"FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNNKSSSSVVVVAAAADDEEGGGG",
+		"-----------------------------------M---------------M------------",
+	},
+	10: {
+		10, "Euplotid Nuclear",
+		"FFLLSSSSYY**CCCWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"-----------------------------------M----------------------------",
+	},
+	11: {
+		11, "Bacterial, Archaeal and Plant Plastid",
+		"FFLLSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"---M---------------M------------MMMM---------------M------------",
+	},
+	12: {
+		12, "Alternative Yeast Nuclear",
+		"FFLLSSSSYY**CC*WLLLSPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"-------------------M---------------M----------------------------",
+	},
+	13: {
+		13, "Ascidian Mitochondrial",
+		"FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNKKSSGGVVVVAAAADDEEGGGG",
+		"---M------------------------------MM---------------M------------",
+	},
+	14: {
+		14, "Alternative Flatworm Mitochondrial",
+		"FFLLSSSSYYY*CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNNKSSSSVVVVAAAADDEEGGGG",
+		"-----------------------------------M----------------------------",
+	},
+	15: {
+		15, "Blepharisma Macronuclear",
+		"FFLLSSSSYY*QCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"-----------------------------------M----------------------------",
+	},
+	16: {
+		16, "Chlorophycean Mitochondrial",
+		"FFLLSSSSYY*LCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"-----------------------------------M----------------------------",
+	},
+	21: {
+		21, "Trematode Mitochondrial",
+		"FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNNKSSSSVVVVAAAADDEEGGGG",
+		"-----------------------------------M---------------M------------",
+	},
+	22: {
+		22, "Scenedesmus obliquus Mitochondrial",
+		"FFLLSS*SYY*LCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"-----------------------------------M----------------------------",
+	},
+	23: {
+		23, "Thraustochytrium Mitochondrial",
+		"FF*LSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"--------------------------------M--M---------------M------------",
+	},
+	24: {
+		24, "Rhabdopleuridae Mitochondrial",
+		"FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSSKVVVVAAAADDEEGGGG",
+		"---M---------------M---------------M---------------M------------",
+	},
+	25: {
+		25, "Candidate Division SR1 and Gracilibacteria",
+		"FFLLSSSSYY**CCGWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"---M-------------------------------M---------------M------------",
+	},
+	26: {
+		26, "Pachysolen tannophilus Nuclear",
+		"FFLLSSSSYY**CC*WLLLAPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"-------------------M---------------M----------------------------",
+	},
+	27: {
+		27, "Karyorelict Nuclear",
+		"FFLLSSSSYYQQCCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"-----------------------------------M----------------------------",
+	},
+	28: {
+		28, "Condylostoma Nuclear",
+		"FFLLSSSSYYQQCCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"-----------------------------------M----------------------------",
+	},
+	29: {
+		29, "Mesodinium Nuclear",
+		"FFLLSSSSYYYYCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"-----------------------------------M----------------------------",
+	},
+	30: {
+		30, "Peritrich Nuclear",
+		"FFLLSSSSYYEECC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"-----------------------------------M----------------------------",
+	},
+	31: {
+		31, "Blastocrithidia Nuclear",
+		"FFLLSSSSYYEECCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"-----------------------------------M----------------------------",
+	},
+	32: {
+		32, "Balanophoraceae Plastid",
+		"FFLLSSSSYY*WCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"---M---------------M------------MMMM---------------M------------",
+	},
+	33: {
+		33, "Cephalodiscidae Mitochondrial",
+		"FFLLSSSSYYY*CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSSKVVVVAAAADDEEGGGG",
+		"---M---------------M---------------M---------------M------------",
+	},
+}
+
+// codonBases maps each nucleotide symbol to the indices of the bases it
+// represents in the order of `TCAG`.
+var codonBases = map[byte][]int{
+	't': {0}, 'u': {0}, 'c': {1}, 'a': {2}, 'g': {3},
+	'y': {0, 1}, 'w': {0, 2}, 'k': {0, 3}, 'm': {1, 2}, 's': {1, 3}, 'r': {2, 3},
+	'h': {0, 1, 2}, 'b': {0, 1, 3}, 'd': {0, 2, 3}, 'v': {1, 2, 3},
+	'n': {0, 1, 2, 3},
+}
+
+func codonIndices(codon []byte) []int {
+	indices := []int{0}
+	for _, c := range codon {
+		bases, ok := codonBases[c|0x20]
+		if !ok {
+			return nil
+		}
+		next := make([]int, 0, len(indices)*len(bases))
+		for _, i := range indices {
+			for _, j := range bases {
+				next = append(next, i*4+j)
+			}
+		}
+		indices = next
+	}
+	return indices
+}
+
+func lookupCodon(table string, codon []byte) byte {
+	indices := codonIndices(codon)
+	if len(indices) == 0 {
+		return 'X'
+	}
+	c := table[indices[0]]
+	for _, i := range indices[1:] {
+		if table[i] != c {
+			return 'X'
+		}
+	}
+	return c
+}
+
+// Codon returns the amino acid encoded by the given codon. An ambiguous codon
+// will be translated only if all of the codons it represents encode the same
+// amino acid, and to `X` otherwise.
+func (gc GeneticCode) Codon(codon []byte) byte {
+	if len(codon) != 3 {
+		return 'X'
+	}
+	return lookupCodon(gc.AminoAcids, codon)
+}
+
+// IsStart tests if the given codon is a start codon.
+func (gc GeneticCode) IsStart(codon []byte) bool {
+	return len(codon) == 3 && lookupCodon(gc.Starts, codon) == 'M'
+}
+
+// Translate the given nucleotide sequence into an amino acid sequence. If
+// start is true, the first codon will be translated as methionine if it is a
+// start codon. Any trailing bases which do not form a complete codon are
+// ignored.
+func (gc GeneticCode) Translate(p []byte, start bool) []byte {
+	q := make([]byte, 0, len(p)/3)
+	for i := 0; i+3 <= len(p); i += 3 {
+		codon := p[i : i+3]
+		if i == 0 && start && gc.IsStart(codon) {
+			q = append(q, 'M')
+		} else {
+			q = append(q, gc.Codon(codon))
+		}
+	}
+	return q
+}
+
+func locationPartial(loc Location) Partial {
+	switch v := loc.(type) {
+	case Ranged:
+		return v.Partial
+	case Complemented:
+		p := locationPartial(v.Location)
+		return Partial{p.Partial3, p.Partial5}
+	case Joined:
+		if len(v) == 0 {
+			return Complete
+		}
+		head, tail := locationPartial(v[0]), locationPartial(v[len(v)-1])
+		return Partial{head.Partial5, tail.Partial3}
+	case Ordered:
+		if len(v) == 0 {
+			return Complete
+		}
+		head, tail := locationPartial(v[0]), locationPartial(v[len(v)-1])
+		return Partial{head.Partial5, tail.Partial3}
+	default:
+		return Complete
+	}
+}
+
+func featureInt(f Feature, name string, def int) (int, error) {
+	values := f.Props.Get(name)
+	if len(values) == 0 {
+		return def, nil
+	}
+	n, err := strconv.Atoi(values[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid /%s value %q: %v", name, values[0], err)
+	}
+	return n, nil
+}
+
+// Translate the given feature of the sequence into an amino acid sequence.
+// The genetic code table is taken from the `/transl_table` qualifier of the
+// feature, and the given table is used if the qualifier is absent. The
+// reading frame is set by the `/codon_start` qualifier. If the 5' end of the
+// feature is complete, the first codon will be translated as methionine if it
+// is a start codon. If the 3' end of the feature is complete, the terminal
+// stop codon will be removed from the translation.
+func Translate(seq Sequence, f Feature, table int) (Sequence, error) {
+	id, err := featureInt(f, "transl_table", table)
+	if err != nil {
+		return nil, err
+	}
+	code, ok := GeneticCodes[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown genetic code table: %d", id)
+	}
+
+	start, err := featureInt(f, "codon_start", 1)
+	if err != nil {
+		return nil, err
+	}
+	if start < 1 || 3 < start {
+		return nil, fmt.Errorf("invalid /codon_start value: %d", start)
+	}
+
+	p := f.Loc.Region().Locate(seq).Bytes()
+	if len(p) < start-1 {
+		return New(nil, nil, nil), nil
+	}
+
+	partial := locationPartial(f.Loc)
+	q := code.Translate(p[start-1:], start == 1 && !partial.Partial5)
+	if !partial.Partial3 && len(q) > 0 && q[len(q)-1] == '*' {
+		q = q[:len(q)-1]
+	}
+
+	return New(nil, nil, q), nil
+}
@@ -0,0 +1,116 @@
+package gts
+
+import (
+	"testing"
+
+	"github.com/go-gts/gts/internal/testutils"
+)
+
+func TestGeneticCodes(t *testing.T) {
+	for id, code := range GeneticCodes {
+		if code.ID != id {
+			t.Errorf("GeneticCodes[%d].ID = %d", id, code.ID)
+		}
+		if len(code.AminoAcids) != 64 || len(code.Starts) != 64 {
+			t.Errorf("GeneticCodes[%d] has %d amino acids and %d starts", id, len(code.AminoAcids), len(code.Starts))
+		}
+	}
+}
+
+var codonTests = []struct {
+	in    string
+	out   byte
+	start bool
+}{
+	{"ATG", 'M', true},
+	{"atg", 'M', true},
+	{"AUG", 'M', true},
+	{"TTG", 'L', true},
+	{"TAA", '*', false},
+	{"TGA", '*', false},
+	{"GCN", 'A', false},
+	{"TTR", 'L', false},
+	{"TTN", 'X', false},
+	{"AT", 'X', false},
+	{"A-G", 'X', false},
+}
+
+func TestGeneticCodeCodon(t *testing.T) {
+	code := GeneticCodes[11]
+	for _, tt := range codonTests {
+		out := code.Codon([]byte(tt.in))
+		if out != tt.out {
+			t.Errorf("code.Codon(%q) = %q, want %q", tt.in, out, tt.out)
+		}
+		start := code.IsStart([]byte(tt.in))
+		if start != tt.start {
+			t.Errorf("code.IsStart(%q) = %t, want %t", tt.in, start, tt.start)
+		}
+	}
+}
+
+func translateProps(pairs ...string) Props {
+	props := Props{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		props.Add(pairs[i], pairs[i+1])
+	}
+	return props
+}
+
+var translateTests = []struct {
+	loc   Location
+	props Props
+	table int
+	out   string
+}{
+	{Range(0, 15), Props{}, 1, "MKWL"},
+	{Range(0, 15), Props{}, 11, "MKWL"},
+	{Range(0, 15), translateProps("transl_table", "2"), 1, "MKWL"},
+	{PartialRange(0, 15, Partial3), Props{}, 1, "MKWL*"},
+	{Range(3, 15), Props{}, 1, "KWL"},
+	{Range(3, 15), Props{}, 2, "KWL"},
+	{Range(9, 15), Props{}, 11, "M"},
+	{PartialRange(9, 15, Partial5), Props{}, 11, "L"},
+	{Range(9, 15), translateProps("codon_start", "2"), 1, "C"},
+	{Range(0, 15).Complement(), Props{}, 1, "LQPFH"},
+	{Join(Range(0, 3), Range(9, 15)), Props{}, 1, "ML"},
+	{Join(Range(0, 3), Range(6, 9)), Props{}, 1, "MW"},
+	{Join(Range(6, 9), Range(9, 12)), Props{}, 2, "WL"},
+	{Point(0), translateProps("codon_start", "3"), 1, ""},
+}
+
+func TestTranslate(t *testing.T) {
+	seq := New(nil, nil, []byte("ATGAAATGGTTGTAA"))
+	for _, tt := range translateTests {
+		f := NewFeature("CDS", tt.loc, tt.props)
+		out, err := Translate(seq, f, tt.table)
+		if err != nil {
+			t.Errorf("Translate(seq, %v, %d): %v", f, tt.table, err)
+			continue
+		}
+		testutils.Equals(t, string(out.Bytes()), tt.out)
+	}
+}
+
+var translateFailTests = []struct {
+	props Props
+	table int
+}{
+	{Props{}, 0},
+	{Props{}, 7},
+	{translateProps("transl_table", "foo"), 1},
+	{translateProps("transl_table", "8"), 1},
+	{translateProps("codon_start", "foo"), 1},
+	{translateProps("codon_start", "0"), 1},
+	{translateProps("codon_start", "4"), 1},
+}
+
+func TestTranslateFail(t *testing.T) {
+	seq := New(nil, nil, []byte("ATGAAATGGTTGTAA"))
+	for _, tt := range translateFailTests {
+		f := NewFeature("CDS", Range(0, 15), tt.props)
+		if _, err := Translate(seq, f, tt.table); err == nil {
+			t.Errorf("Translate(seq, %v, %d): expected error", f, tt.table)
+		}
+	}
+}
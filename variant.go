@@ -0,0 +1,111 @@
+package gts
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// Variant represents a change in the bases of a sequence. Pos is the position
+// of the first base of Ref in the reference sequence, and Ref is replaced by
+// Alt when the variant is applied. ID is an optional identifier of the variant.
+type Variant struct {
+	Pos int
+	Ref []byte
+	Alt []byte
+	ID  string
+}
+
+// Trim returns the variant with the bases shared by the beginning and end of
+// Ref and Alt removed. The shared leading base required for insertions and
+// deletions in VCF records will be removed.
+func (v Variant) Trim() Variant {
+	ref, alt, pos := v.Ref, v.Alt, v.Pos
+	for len(ref) > 0 && len(alt) > 0 && bytes.EqualFold(ref[:1], alt[:1]) {
+		ref, alt, pos = ref[1:], alt[1:], pos+1
+	}
+	for len(ref) > 0 && len(alt) > 0 && bytes.EqualFold(ref[len(ref)-1:], alt[len(alt)-1:]) {
+		ref, alt = ref[:len(ref)-1], alt[:len(alt)-1]
+	}
+	return Variant{pos, ref, alt, v.ID}
+}
+
+// ApplyVariants applies the variants to the sequence and annotates each of
+// the changes as a `variation` feature. The positions of the variants are
+// relative to the given sequence, and the reference bases of each variant must
+// match the sequence. Substitutions replace the bases in place, and insertions
+// and deletions are applied using Delete and either Embed or Insert depending
+// on the value of embed so that the existing features are shifted accordingly.
+func ApplyVariants(seq Sequence, variants []Variant, embed bool) (Sequence, error) {
+	p := seq.Bytes()
+	vv := make([]Variant, len(variants))
+	for i, v := range variants {
+		if v.Pos < 0 || len(p) < v.Pos+len(v.Ref) {
+			return nil, fmt.Errorf("variant at position %d is out of range", v.Pos+1)
+		}
+		if !bytes.EqualFold(p[v.Pos:v.Pos+len(v.Ref)], v.Ref) {
+			return nil, fmt.Errorf(
+				"reference bases of variant at position %d do not match: expected %q, got %q",
+				v.Pos+1, string(v.Ref), string(p[v.Pos:v.Pos+len(v.Ref)]),
+			)
+		}
+		vv[i] = v.Trim()
+	}
+
+	sort.SliceStable(vv, func(i, j int) bool {
+		return vv[i].Pos < vv[j].Pos
+	})
+
+	for i := 1; i < len(vv); i++ {
+		if vv[i].Pos < vv[i-1].Pos+len(vv[i-1].Ref) {
+			return nil, fmt.Errorf("variants at positions %d and %d overlap", vv[i-1].Pos+1, vv[i].Pos+1)
+		}
+	}
+
+	lower := len(p) > 0 && 'a' <= p[0] && p[0] <= 'z'
+
+	insert := Insert
+	if embed {
+		insert = Embed
+	}
+
+	// Apply the variants from the end so that the positions of the remaining
+	// variants are not affected.
+	for i := len(vv) - 1; i >= 0; i-- {
+		v := vv[i]
+		if len(v.Ref) == 0 && len(v.Alt) == 0 {
+			continue
+		}
+
+		alt := bytes.ToUpper(v.Alt)
+		if lower {
+			alt = bytes.ToLower(v.Alt)
+		}
+
+		if len(v.Ref) == len(alt) {
+			q := make([]byte, Len(seq))
+			copy(q, seq.Bytes())
+			copy(q[v.Pos:], alt)
+			seq = WithBytes(seq, q)
+		} else {
+			if len(v.Ref) > 0 {
+				seq = Delete(seq, v.Pos, len(v.Ref))
+			}
+			if len(alt) > 0 {
+				seq = insert(seq, v.Pos, New(nil, nil, alt))
+			}
+		}
+
+		props := Props{}
+		props.Add("replace", string(alt))
+		if v.ID != "" && v.ID != "." {
+			props.Add("note", v.ID)
+		}
+
+		loc := editLocation(v.Pos, len(alt))
+		ff := seq.Features().Insert(NewFeature("variation", loc, props))
+		seq = WithFeatures(seq, ff)
+	}
+
+	return seq, nil
+}
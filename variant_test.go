@@ -0,0 +1,97 @@
+package gts
+
+import (
+	"testing"
+
+	"github.com/go-gts/gts/internal/testutils"
+)
+
+var variantTrimTests = []struct {
+	in, out Variant
+}{
+	{
+		Variant{5, []byte("A"), []byte("T"), ""},
+		Variant{5, []byte("A"), []byte("T"), ""},
+	},
+	{
+		Variant{5, []byte("A"), []byte("ATT"), ""},
+		Variant{6, []byte{}, []byte("TT"), ""},
+	},
+	{
+		Variant{5, []byte("ATT"), []byte("A"), ""},
+		Variant{6, []byte("TT"), []byte{}, ""},
+	},
+	{
+		Variant{5, []byte("ACGT"), []byte("AGGT"), ""},
+		Variant{6, []byte("C"), []byte("G"), ""},
+	},
+	{
+		Variant{5, []byte("a"), []byte("A"), ""},
+		Variant{6, []byte{}, []byte{}, ""},
+	},
+}
+
+func TestVariantTrim(t *testing.T) {
+	for _, tt := range variantTrimTests {
+		testutils.Equals(t, tt.in.Trim(), tt.out)
+	}
+}
+
+func TestApplyVariants(t *testing.T) {
+	p := []byte("aaaaacccccgggggttttt")
+	ff := FeatureSlice{NewFeature("CDS", Range(5, 15), Props{})}
+	seq := New(nil, ff, p)
+
+	variants := []Variant{
+		{16, []byte("TTT"), []byte("T"), "del"},
+		{6, []byte("C"), []byte("T"), "rs1"},
+		{9, []byte("C"), []byte("CAA"), "."},
+	}
+
+	out, err := ApplyVariants(seq, variants, true)
+	if err != nil {
+		t.Fatalf("ApplyVariants(): %v", err)
+	}
+	testutils.Equals(t, string(out.Bytes()), "aaaaactcccaagggggttt")
+	testutils.Equals(t, string(p), "aaaaacccccgggggttttt")
+
+	cds := out.Features().Filter(Key("CDS"))
+	testutils.Equals(t, cds[0].Loc, Location(Range(5, 17)))
+
+	vv := out.Features().Filter(Key("variation"))
+	testutils.Equals(t, len(vv), 3)
+	exp := map[string]Feature{
+		"7":      NewFeature("variation", Point(6), Props{{"replace", "t"}, {"note", "rs1"}}),
+		"11..12": NewFeature("variation", Range(10, 12), Props{{"replace", "aa"}}),
+		"19^20":  NewFeature("variation", Between(19), Props{{"replace", ""}, {"note", "del"}}),
+	}
+	for _, f := range vv {
+		testutils.Equals(t, f, exp[f.Loc.String()])
+	}
+
+	out, err = ApplyVariants(seq, variants, false)
+	if err != nil {
+		t.Fatalf("ApplyVariants(): %v", err)
+	}
+	cds = out.Features().Filter(Key("CDS"))
+	testutils.Equals(t, cds[0].Loc, Location(Join(Range(5, 10), Range(12, 17))))
+}
+
+var applyVariantsFailTests = [][]Variant{
+	{{18, []byte("TTT"), []byte("T"), ""}},
+	{{-1, []byte("A"), []byte("T"), ""}},
+	{{5, []byte("A"), []byte("T"), ""}},
+	{
+		{5, []byte("CCC"), []byte("C"), ""},
+		{6, []byte("C"), []byte("T"), ""},
+	},
+}
+
+func TestApplyVariantsFail(t *testing.T) {
+	seq := New(nil, nil, []byte("aaaaacccccgggggttttt"))
+	for _, variants := range applyVariantsFailTests {
+		if _, err := ApplyVariants(seq, variants, true); err == nil {
+			t.Errorf("expected error in ApplyVariants(%v)", variants)
+		}
+	}
+}